@@ -39,6 +39,8 @@ from:
     # labelsOnly: true
     ## Move issues instead of copying them
     # moveIssues: true
+    ## Copy merge requests too
+    # mergeRequests: true
 to:
     url: https://gitlab.myotherdomain.com
     token: anothertoken
@@ -127,6 +129,45 @@ Options:
 	if len(pstats.Labels) > 0 {
 		fmt.Printf("source: %d label(s): %s\n", len(pstats.Labels), map2Human(pstats.Labels))
 	}
+	if c.SrcPrj.FeatureFlags {
+		if err := pstats.ComputeFeatureFlags(m.Endpoint.SrcClient); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("source: %d feature flag(s): %s\n", len(pstats.FeatureFlags), map2Human(pstats.FeatureFlags))
+	}
+
+	if c.SrcPrj.Variables {
+		if err := pstats.ComputeVariables(m.Endpoint.SrcClient); err != nil {
+			log.Fatal(err)
+		}
+		// Names only, values are secrets.
+		fmt.Printf("source: %d CI/CD variable(s): %s\n", len(pstats.Variables), map2Human(pstats.Variables))
+	}
+
+	if c.SrcPrj.Hooks {
+		if err := pstats.ComputeHooks(m.Endpoint.SrcClient); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("source: %d webhook(s)\n", len(pstats.Hooks))
+		for _, h := range pstats.Hooks {
+			name := migration.HookName(h.URL)
+			if u := c.SrcPrj.HookURL(h.URL); u != h.URL {
+				name += " -> " + migration.HookName(u)
+			}
+			fmt.Printf("  - #%d %s\n", h.ID, name)
+		}
+	}
+
+	if len(c.SrcPrj.Settings) > 0 {
+		changes, err := m.SettingsDiff()
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("target: %d project setting(s) to change\n", len(changes))
+		for _, ch := range changes {
+			fmt.Printf("  - %s: %q -> %q\n", ch.Name, ch.From, ch.To)
+		}
+	}
 
 	if !c.SrcPrj.LabelsOnly {
 		fmt.Printf("source: counting notes (comments), can take a while ... ")
@@ -139,21 +180,33 @@ Options:
 	if !*apply {
 		if c.SrcPrj.LabelsOnly {
 			fmt.Println("Will copy labels only.")
+			if c.SrcPrj.Boards {
+				fmt.Println("Will copy issue boards too.")
+			}
 		} else {
 			if c.SrcPrj.MilestonesOnly {
 				fmt.Println("Will copy milestones only.")
+				if c.SrcPrj.Boards {
+					fmt.Println("Will copy issue boards too.")
+				}
 			} else {
 				action := "Copy"
 				if c.SrcPrj.MoveIssues {
 					action = "Move"
 				}
 				fmt.Printf(`Those actions will be performed:
-- Copy milestones if not existing on target
-- Copy all source labels on target
+- Copy milestones if not existing on target (by title), update the existing ones
+- Copy all source labels on target, with their priority
 - %s all issues (or those specified) if not existing on target (by title)
 - Copy closed status on issues, if any
-- Set issue's assignee (if user exists) and milestone, if any
-- Copy notes (attached to issues)
+- Set issue's assignees (those existing on target, others are reported) and milestone, if any
+- Copy notes (attached to issues), keeping discussion threads
+- Copy attachments linked in issues and notes
+- Copy award emoji on issues and notes
+- Copy time estimate and time spent
+- Copy due date, weight, confidential flag, discussion lock, type and health status of issues
+- Copy label, milestone, state and weight changes of issues as a timeline note
+- Recreate links between copied issues, add a note for links to other issues
 `, action)
 				if c.SrcPrj.AutoCloseIssues {
 					fmt.Println("- Auto-close source issues")
@@ -162,6 +215,51 @@ Options:
 					fmt.Println("- Add a note with a link to new issue")
 					fmt.Println("- Use the link text template: " + c.SrcPrj.LinkToTargetIssueText)
 				}
+				if len(c.SrcPrj.Settings) > 0 {
+					fmt.Println("- Apply the source project settings listed above to target: " + strings.Join(c.SrcPrj.Settings, ", "))
+				}
+				if c.SrcPrj.Members {
+					fmt.Println("- Add source project members to target (by username), with their access level")
+				}
+				if c.SrcPrj.Boards {
+					fmt.Println("- Copy issue boards if not existing on target (by name), with their lists")
+				}
+				if c.SrcPrj.Group != "" {
+					fmt.Printf("- Copy epics of group %s to group %s, and attach the copied issues to them\n", c.SrcPrj.Group, c.DstPrj.Group)
+				}
+				if c.SrcPrj.MergeRequests {
+					fmt.Println("- Copy merge requests if not existing on target (by title), as issues if branches are missing on target")
+				}
+				if c.SrcPrj.Wiki {
+					fmt.Println("- Copy wiki pages if not existing on target (by slug), with their attachments")
+				}
+				if c.SrcPrj.Snippets {
+					fmt.Println("- Copy snippets if not existing on target (by title), with their files and notes")
+				}
+				if c.SrcPrj.Releases {
+					fmt.Println("- Copy releases if not existing on target (by tag), when their tag exists on target")
+				}
+				if c.SrcPrj.Variables {
+					fmt.Println("- Copy CI/CD variables if not existing on target (by key and environment scope)")
+				}
+				if c.SrcPrj.Environments {
+					fmt.Println("- Copy environments if not existing on target (by name), and deploy freeze periods (by schedule)")
+				}
+				if c.SrcPrj.FeatureFlags {
+					fmt.Println("- Copy feature flags if not existing on target (by name), with their strategies and scopes")
+				}
+				if c.SrcPrj.ProtectedRefs {
+					fmt.Println("- Protect branches and tags with their access levels, protecting again those with other access levels on target (by name)")
+				}
+				if c.SrcPrj.ApprovalRules {
+					fmt.Println("- Copy approval rules if not existing on target (by name), with the approvers found on target")
+				}
+				if c.SrcPrj.Badges {
+					fmt.Println("- Copy project badges if not existing on target (by URL), with source host links pointing to the target host")
+				}
+				if c.SrcPrj.Hooks {
+					fmt.Println("- Copy webhooks if not existing on target (by URL), with their events and SSL verification setting")
+				}
 			}
 		}
 
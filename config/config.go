@@ -46,6 +46,9 @@ func Parse(r io.Reader) (*Config, error) {
 	if err := c.DstPrj.checkData("destination"); err != nil {
 		return nil, err
 	}
+	if c.SrcPrj.Group != "" && c.DstPrj.Group == "" {
+		return nil, fmt.Errorf("missing destination group, required to copy the epics of source group '%s'", c.SrcPrj.Group)
+	}
 	if err := c.checkUserTokens(); err != nil {
 		return nil, err
 	}
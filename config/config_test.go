@@ -1,6 +1,7 @@
 package config
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -13,6 +14,30 @@ func TestParseConfig(t *testing.T) {
 	require.NotNil(err)
 }
 
+func TestParseGroups(t *testing.T) {
+	assert := assert.New(t)
+
+	conf := `
+from:
+    url: https://gitlab.mydomain.com
+    token: sourcetoken
+    project: source/project
+    group: source
+to:
+    url: https://gitlab.mydomain.com
+    token: desttoken
+    project: dest/project
+`
+	_, err := Parse(strings.NewReader(conf))
+	assert.Error(err)
+
+	c, err := Parse(strings.NewReader(conf + "    group: dest\n"))
+	if assert.NoError(err) {
+		assert.Equal("source", c.SrcPrj.Group)
+		assert.Equal("dest", c.DstPrj.Group)
+	}
+}
+
 func TestParseIssues(t *testing.T) {
 	assert := assert.New(t)
 
@@ -72,3 +97,24 @@ func TestMatches(t *testing.T) {
 		}
 	}
 }
+
+func TestHookURL(t *testing.T) {
+	p := &project{HookHosts: map[string]string{
+		"ci.old.com":      "ci.new.com",
+		"chat.old.com:81": "chat.new.com",
+	}}
+
+	set := []struct {
+		url, expect string
+	}{
+		{"https://ci.old.com/hook?x=1", "https://ci.new.com/hook?x=1"},
+		{"https://ci.old.com:8443/hook", "https://ci.new.com:8443/hook"},
+		{"http://chat.old.com:81/notify", "http://chat.new.com/notify"},
+		{"https://other.com/hook", "https://other.com/hook"},
+	}
+	for _, r := range set {
+		if u := p.HookURL(r.url); u != r.expect {
+			t.Errorf("expected: %s, got: %s", r.expect, u)
+		}
+	}
+}
@@ -30,6 +30,45 @@ type project struct {
 	LinkToTargetIssue bool `yaml:"linkToTargetIssue"`
 	// Optional caption to use for the link text
 	LinkToTargetIssueText string `yaml:"linkToTargetIssueText"`
+	// If true, copy each time spent entry found in the source issue's
+	// system notes, instead of the total time spent per user
+	TimeTrackingDetails bool `yaml:"timeTrackingDetails"`
+	// If true, copy merge requests too (as issues when branches are missing
+	// on target)
+	MergeRequests bool `yaml:"mergeRequests"`
+	// If true, copy the wiki pages too
+	Wiki bool `yaml:"wiki"`
+	// If true, copy the project snippets too
+	Snippets bool `yaml:"snippets"`
+	// If true, copy the releases whose tag exists on target
+	Releases bool `yaml:"releases"`
+	// If true, add the source project members to the target project first
+	Members bool `yaml:"members"`
+	// If true, copy the issue boards and their lists
+	Boards bool `yaml:"boards"`
+	// If true, copy the CI/CD variables
+	Variables bool `yaml:"variables"`
+	// If true, copy the feature flags
+	FeatureFlags bool `yaml:"featureFlags"`
+	// If true, copy the environments and deploy freeze periods
+	Environments bool `yaml:"environments"`
+	// If true, copy the protected branches and tags rules
+	ProtectedRefs bool `yaml:"protectedRefs"`
+	// If true, copy the project-level merge request approval rules
+	ApprovalRules bool `yaml:"approvalRules"`
+	// If true, copy the project badges
+	Badges bool `yaml:"badges"`
+	// If true, copy the project webhooks
+	Hooks bool `yaml:"hooks"`
+	// Optional mapping of source hosts to target hosts, applied to the
+	// webhook URLs
+	HookHosts map[string]string `yaml:"hookHosts"`
+	// Optional list of project settings to apply to the target project
+	// (description, topics, avatar, defaultBranch, features, mergeSettings)
+	Settings []string `yaml:"settings"`
+	// Optional group (path or ID) of the project, whose epics are copied
+	// when set in both source and target sections
+	Group string `yaml:"group"`
 }
 
 // matches checks whether issue is part of p.issues. Always
@@ -47,6 +86,27 @@ func (p *project) Matches(issue int) bool {
 	return false
 }
 
+// HookURL returns rawURL with its host replaced according to p.HookHosts.
+// rawURL is returned as is if it can't be parsed or its host is not mapped.
+func (p *project) HookURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	host, ok := p.HookHosts[u.Host]
+	if !ok {
+		host, ok = p.HookHosts[u.Hostname()]
+		if !ok {
+			return rawURL
+		}
+		if port := u.Port(); port != "" && !strings.Contains(host, ":") {
+			host += ":" + port
+		}
+	}
+	u.Host = host
+	return u.String()
+}
+
 // parseIssues ensure issue items are valid input data, i.e castable
 // to int, ranges allowed.
 func (p *project) parseIssues() error {
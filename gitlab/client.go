@@ -1,9 +1,15 @@
 package gitlab
 
 import (
+	"bytes"
 	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"net/url"
+	"strings"
 
 	"github.com/hashicorp/go-retryablehttp"
 	"github.com/rotisserie/eris"
@@ -64,6 +70,15 @@ func (c *client) GetProject(
 	return c.c.Projects.GetProject(id, opt, options...)
 }
 
+// EditProject updates the settings of a project.
+func (c *client) EditProject(
+	id interface{},
+	opt *glab.EditProjectOptions,
+	options ...glab.RequestOptionFunc,
+) (*glab.Project, *glab.Response, error) {
+	return c.c.Projects.EditProject(id, opt, options...)
+}
+
 // CreateLabel creates a label.
 func (c *client) CreateLabel(
 	id interface{},
@@ -82,6 +97,51 @@ func (c *client) ListLabels(
 	return c.c.Labels.ListLabels(id, opt, options...)
 }
 
+// UpdateLabel updates a label.
+func (c *client) UpdateLabel(
+	id interface{},
+	opt *glab.UpdateLabelOptions,
+	options ...glab.RequestOptionFunc,
+) (*glab.Label, *glab.Response, error) {
+	return c.c.Labels.UpdateLabel(id, opt, options...)
+}
+
+// labelPriority holds the priority of a label, which glab.Label can't tell
+// apart from no priority at all (both decoded as 0).
+type labelPriority struct {
+	Name     string `json:"name"`
+	Priority *int   `json:"priority"`
+}
+
+// ListLabelPriorities returns the priorities of the prioritized labels of a
+// project, by label name.
+func (c *client) ListLabelPriorities(
+	id interface{},
+	opt *glab.ListLabelsOptions,
+	options ...glab.RequestOptionFunc,
+) (map[string]int, *glab.Response, error) {
+	p, err := projectPath(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	req, err := c.c.NewRequest(http.MethodGet, p+"/labels", opt, options)
+	if err != nil {
+		return nil, nil, err
+	}
+	var ls []*labelPriority
+	resp, err := c.c.Do(req, &ls)
+	if err != nil {
+		return nil, resp, err
+	}
+	prios := make(map[string]int)
+	for _, l := range ls {
+		if l.Priority != nil {
+			prios[l.Name] = *l.Priority
+		}
+	}
+	return prios, resp, nil
+}
+
 // ListMilestones list all milestones.
 func (c *client) ListMilestones(
 	id interface{},
@@ -146,6 +206,115 @@ func (c *client) CreateIssue(
 	return c.c.Issues.CreateIssue(pid, opt, options...)
 }
 
+// ListIssueRelations lists the issues linked to an issue.
+func (c *client) ListIssueRelations(
+	pid interface{},
+	issue int,
+	options ...glab.RequestOptionFunc,
+) ([]*glab.IssueRelation, *glab.Response, error) {
+	return c.c.IssueLinks.ListIssueRelations(pid, issue, options...)
+}
+
+// CreateIssueLink creates a link between two issues.
+func (c *client) CreateIssueLink(
+	pid interface{},
+	issue int,
+	opt *glab.CreateIssueLinkOptions,
+	options ...glab.RequestOptionFunc,
+) (*glab.IssueLink, *glab.Response, error) {
+	return c.c.IssueLinks.CreateIssueLink(pid, issue, opt, options...)
+}
+
+// projectPath returns the API path of project pid.
+func projectPath(pid interface{}) (string, error) {
+	switch id := pid.(type) {
+	case int:
+		return fmt.Sprintf("projects/%d", id), nil
+	case string:
+		return "projects/" + url.PathEscape(id), nil
+	}
+	return "", fmt.Errorf("invalid ID type %#v, the ID must be an int or a string", pid)
+}
+
+// issueHealthStatus holds the health status of an issue, which glab.Issue
+// doesn't support.
+type issueHealthStatus struct {
+	HealthStatus *string `url:"health_status,omitempty" json:"health_status,omitempty"`
+}
+
+// GetIssueWithHealthStatus returns an issue along with its health status, if
+// any, both read from the same response.
+func (c *client) GetIssueWithHealthStatus(
+	pid interface{},
+	issue int,
+	options ...glab.RequestOptionFunc,
+) (*glab.Issue, string, *glab.Response, error) {
+	p, err := projectPath(pid)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	req, err := c.c.NewRequest(http.MethodGet, fmt.Sprintf("%s/issues/%d", p, issue), nil, options)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	var body bytes.Buffer
+	resp, err := c.c.Do(req, &body)
+	if err != nil {
+		return nil, "", resp, err
+	}
+	i := new(glab.Issue)
+	if err := json.Unmarshal(body.Bytes(), i); err != nil {
+		return nil, "", resp, err
+	}
+	h := new(issueHealthStatus)
+	if err := json.Unmarshal(body.Bytes(), h); err != nil {
+		return nil, "", resp, err
+	}
+	if h.HealthStatus == nil {
+		return i, "", resp, nil
+	}
+	return i, *h.HealthStatus, resp, nil
+}
+
+// SetIssueHealthStatus sets the health status of an issue.
+func (c *client) SetIssueHealthStatus(
+	pid interface{},
+	issue int,
+	status string,
+	options ...glab.RequestOptionFunc,
+) (*glab.Response, error) {
+	p, err := projectPath(pid)
+	if err != nil {
+		return nil, err
+	}
+	opt := &issueHealthStatus{HealthStatus: &status}
+	req, err := c.c.NewRequest(http.MethodPut, fmt.Sprintf("%s/issues/%d", p, issue), opt, options)
+	if err != nil {
+		return nil, err
+	}
+	return c.c.Do(req, nil)
+}
+
+// SetTimeEstimate sets the time estimate of an issue.
+func (c *client) SetTimeEstimate(
+	pid interface{},
+	issue int,
+	opt *glab.SetTimeEstimateOptions,
+	options ...glab.RequestOptionFunc,
+) (*glab.TimeStats, *glab.Response, error) {
+	return c.c.Issues.SetTimeEstimate(pid, issue, opt, options...)
+}
+
+// AddSpentTime adds spent time to an issue.
+func (c *client) AddSpentTime(
+	pid interface{},
+	issue int,
+	opt *glab.AddSpentTimeOptions,
+	options ...glab.RequestOptionFunc,
+) (*glab.TimeStats, *glab.Response, error) {
+	return c.c.Issues.AddSpentTime(pid, issue, opt, options...)
+}
+
 // ListUsers lists all users.
 func (c *client) ListUsers(
 	opt *glab.ListUsersOptions,
@@ -154,6 +323,24 @@ func (c *client) ListUsers(
 	return c.c.Users.ListUsers(opt, opts...)
 }
 
+// GetUser returns a user.
+func (c *client) GetUser(
+	user int,
+	opt glab.GetUsersOptions,
+	options ...glab.RequestOptionFunc,
+) (*glab.User, *glab.Response, error) {
+	return c.c.Users.GetUser(user, opt, options...)
+}
+
+// GetGroup returns a group.
+func (c *client) GetGroup(
+	gid interface{},
+	opt *glab.GetGroupOptions,
+	options ...glab.RequestOptionFunc,
+) (*glab.Group, *glab.Response, error) {
+	return c.c.Groups.GetGroup(gid, opt, options...)
+}
+
 // ListIssueNotes list issue notes.
 func (c *client) ListIssueNotes(
 	pid interface{},
@@ -184,6 +371,899 @@ func (c *client) UpdateIssue(
 	return c.c.Issues.UpdateIssue(pid, issue, opt, options...)
 }
 
+// ListProjectMergeRequests lists all merge requests of a project.
+func (c *client) ListProjectMergeRequests(
+	pid interface{},
+	opt *glab.ListProjectMergeRequestsOptions,
+	options ...glab.RequestOptionFunc,
+) ([]*glab.MergeRequest, *glab.Response, error) {
+	return c.c.MergeRequests.ListProjectMergeRequests(pid, opt, options...)
+}
+
+// GetMergeRequest returns a merge request.
+func (c *client) GetMergeRequest(
+	pid interface{},
+	mergeRequest int,
+	opt *glab.GetMergeRequestsOptions,
+	options ...glab.RequestOptionFunc,
+) (*glab.MergeRequest, *glab.Response, error) {
+	return c.c.MergeRequests.GetMergeRequest(pid, mergeRequest, opt, options...)
+}
+
+// CreateMergeRequest creates a merge request.
+func (c *client) CreateMergeRequest(
+	pid interface{},
+	opt *glab.CreateMergeRequestOptions,
+	options ...glab.RequestOptionFunc,
+) (*glab.MergeRequest, *glab.Response, error) {
+	return c.c.MergeRequests.CreateMergeRequest(pid, opt, options...)
+}
+
+// UpdateMergeRequest updates a merge request.
+func (c *client) UpdateMergeRequest(
+	pid interface{},
+	mergeRequest int,
+	opt *glab.UpdateMergeRequestOptions,
+	options ...glab.RequestOptionFunc,
+) (*glab.MergeRequest, *glab.Response, error) {
+	return c.c.MergeRequests.UpdateMergeRequest(pid, mergeRequest, opt, options...)
+}
+
+// CreateMergeRequestNote creates a note for a merge request.
+func (c *client) CreateMergeRequestNote(
+	pid interface{},
+	mergeRequest int,
+	opt *glab.CreateMergeRequestNoteOptions,
+	options ...glab.RequestOptionFunc,
+) (*glab.Note, *glab.Response, error) {
+	return c.c.Notes.CreateMergeRequestNote(pid, mergeRequest, opt, options...)
+}
+
+// ListIssueDiscussions lists all discussions of an issue.
+func (c *client) ListIssueDiscussions(
+	pid interface{},
+	issue int,
+	opt *glab.ListIssueDiscussionsOptions,
+	options ...glab.RequestOptionFunc,
+) ([]*glab.Discussion, *glab.Response, error) {
+	return c.c.Discussions.ListIssueDiscussions(pid, issue, opt, options...)
+}
+
+// CreateIssueDiscussion starts a new discussion on an issue.
+func (c *client) CreateIssueDiscussion(
+	pid interface{},
+	issue int,
+	opt *glab.CreateIssueDiscussionOptions,
+	options ...glab.RequestOptionFunc,
+) (*glab.Discussion, *glab.Response, error) {
+	return c.c.Discussions.CreateIssueDiscussion(pid, issue, opt, options...)
+}
+
+// AddIssueDiscussionNote adds a reply to an issue discussion.
+func (c *client) AddIssueDiscussionNote(
+	pid interface{},
+	issue int,
+	discussion string,
+	opt *glab.AddIssueDiscussionNoteOptions,
+	options ...glab.RequestOptionFunc,
+) (*glab.Note, *glab.Response, error) {
+	return c.c.Discussions.AddIssueDiscussionNote(pid, issue, discussion, opt, options...)
+}
+
+// ListMergeRequestDiscussions lists all discussions of a merge request.
+func (c *client) ListMergeRequestDiscussions(
+	pid interface{},
+	mergeRequest int,
+	opt *glab.ListMergeRequestDiscussionsOptions,
+	options ...glab.RequestOptionFunc,
+) ([]*glab.Discussion, *glab.Response, error) {
+	return c.c.Discussions.ListMergeRequestDiscussions(pid, mergeRequest, opt, options...)
+}
+
+// CreateMergeRequestDiscussion starts a new discussion on a merge request.
+func (c *client) CreateMergeRequestDiscussion(
+	pid interface{},
+	mergeRequest int,
+	opt *glab.CreateMergeRequestDiscussionOptions,
+	options ...glab.RequestOptionFunc,
+) (*glab.Discussion, *glab.Response, error) {
+	return c.c.Discussions.CreateMergeRequestDiscussion(pid, mergeRequest, opt, options...)
+}
+
+// AddMergeRequestDiscussionNote adds a reply to a merge request discussion.
+func (c *client) AddMergeRequestDiscussionNote(
+	pid interface{},
+	mergeRequest int,
+	discussion string,
+	opt *glab.AddMergeRequestDiscussionNoteOptions,
+	options ...glab.RequestOptionFunc,
+) (*glab.Note, *glab.Response, error) {
+	return c.c.Discussions.AddMergeRequestDiscussionNote(pid, mergeRequest, discussion, opt, options...)
+}
+
+// ResolveMergeRequestDiscussion resolves or unresolves a merge request
+// discussion.
+func (c *client) ResolveMergeRequestDiscussion(
+	pid interface{},
+	mergeRequest int,
+	discussion string,
+	opt *glab.ResolveMergeRequestDiscussionOptions,
+	options ...glab.RequestOptionFunc,
+) (*glab.Discussion, *glab.Response, error) {
+	return c.c.Discussions.ResolveMergeRequestDiscussion(pid, mergeRequest, discussion, opt, options...)
+}
+
+// ListIssueAwardEmoji lists the award emoji of an issue.
+func (c *client) ListIssueAwardEmoji(
+	pid interface{},
+	issue int,
+	opt *glab.ListAwardEmojiOptions,
+	options ...glab.RequestOptionFunc,
+) ([]*glab.AwardEmoji, *glab.Response, error) {
+	return c.c.AwardEmoji.ListIssueAwardEmoji(pid, issue, opt, options...)
+}
+
+// CreateIssueAwardEmoji awards an emoji to an issue.
+func (c *client) CreateIssueAwardEmoji(
+	pid interface{},
+	issue int,
+	opt *glab.CreateAwardEmojiOptions,
+	options ...glab.RequestOptionFunc,
+) (*glab.AwardEmoji, *glab.Response, error) {
+	return c.c.AwardEmoji.CreateIssueAwardEmoji(pid, issue, opt, options...)
+}
+
+// ListIssuesAwardEmojiOnNote lists the award emoji of an issue note.
+func (c *client) ListIssuesAwardEmojiOnNote(
+	pid interface{},
+	issue, note int,
+	opt *glab.ListAwardEmojiOptions,
+	options ...glab.RequestOptionFunc,
+) ([]*glab.AwardEmoji, *glab.Response, error) {
+	return c.c.AwardEmoji.ListIssuesAwardEmojiOnNote(pid, issue, note, opt, options...)
+}
+
+// CreateIssuesAwardEmojiOnNote awards an emoji to an issue note.
+func (c *client) CreateIssuesAwardEmojiOnNote(
+	pid interface{},
+	issue, note int,
+	opt *glab.CreateAwardEmojiOptions,
+	options ...glab.RequestOptionFunc,
+) (*glab.AwardEmoji, *glab.Response, error) {
+	return c.c.AwardEmoji.CreateIssuesAwardEmojiOnNote(pid, issue, note, opt, options...)
+}
+
+// ListIssueLabelEvents lists the label events of an issue.
+func (c *client) ListIssueLabelEvents(
+	pid interface{},
+	issue int,
+	opt *glab.ListLabelEventsOptions,
+	options ...glab.RequestOptionFunc,
+) ([]*glab.LabelEvent, *glab.Response, error) {
+	return c.c.ResourceLabelEvents.ListIssueLabelEvents(pid, issue, opt, options...)
+}
+
+// ListIssueMilestoneEvents lists the milestone events of an issue.
+func (c *client) ListIssueMilestoneEvents(
+	pid interface{},
+	issue int,
+	opt *glab.ListMilestoneEventsOptions,
+	options ...glab.RequestOptionFunc,
+) ([]*glab.MilestoneEvent, *glab.Response, error) {
+	return c.c.ResourceMilestoneEvents.ListIssueMilestoneEvents(pid, issue, opt, options...)
+}
+
+// ListIssueStateEvents lists the state events of an issue.
+func (c *client) ListIssueStateEvents(
+	pid interface{},
+	issue int,
+	opt *glab.ListStateEventsOptions,
+	options ...glab.RequestOptionFunc,
+) ([]*glab.StateEvent, *glab.Response, error) {
+	return c.c.ResourceStateEvents.ListIssueStateEvents(pid, issue, opt, options...)
+}
+
+// ListIssueWeightEvents lists the weight events of an issue.
+func (c *client) ListIssueWeightEvents(
+	pid interface{},
+	issue int,
+	opt *glab.ListWeightEventsOptions,
+	options ...glab.RequestOptionFunc,
+) ([]*glab.WeightEvent, *glab.Response, error) {
+	return c.c.ResourceWeightEvents.ListIssueWeightEvents(pid, issue, opt, options...)
+}
+
+// UploadFile uploads a file to a project.
+func (c *client) UploadFile(
+	pid interface{},
+	content io.Reader,
+	filename string,
+	options ...glab.RequestOptionFunc,
+) (*glab.ProjectFile, *glab.Response, error) {
+	return c.c.Projects.UploadFile(pid, content, filename, options...)
+}
+
+// errNotAFile is returned by DownloadFile when GitLab answers with a web page
+// instead of the file, which happens when it redirects to its sign-in page.
+var errNotAFile = errors.New("got a web page instead of the file, the token is probably not accepted there")
+
+// DownloadFile downloads the file at rawURL using the client's credentials.
+// Unlike other methods, rawURL may live outside of the API path, like the
+// uploads of a project. Redirects to object storage are followed, but not
+// those to the sign-in page, nor any HTML page.
+func (c *client) DownloadFile(
+	rawURL string,
+	options ...glab.RequestOptionFunc,
+) ([]byte, *glab.Response, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, nil, eris.Wrap(err, "download file")
+	}
+	req, err := c.c.NewRequest(http.MethodGet, "", nil, options)
+	if err != nil {
+		return nil, nil, eris.Wrap(err, "download file")
+	}
+	req.URL = u
+	req.Host = u.Host
+	buf := new(bytes.Buffer)
+	resp, err := c.c.Do(req, buf)
+	if err != nil {
+		return nil, resp, err
+	}
+	if strings.HasSuffix(resp.Request.URL.Path, "/users/sign_in") ||
+		strings.HasPrefix(resp.Header.Get("Content-Type"), "text/html") {
+		return nil, resp, eris.Wrap(errNotAFile, "download file")
+	}
+	return buf.Bytes(), resp, nil
+}
+
+// DownloadUpload downloads an upload of a project, identified by its secret
+// and file name, through the API, unlike DownloadFile which reads it from the
+// project's web path, where the token isn't accepted for private projects.
+func (c *client) DownloadUpload(
+	pid interface{},
+	secret, filename string,
+	options ...glab.RequestOptionFunc,
+) ([]byte, *glab.Response, error) {
+	p, err := projectPath(pid)
+	if err != nil {
+		return nil, nil, err
+	}
+	u := fmt.Sprintf("%s/uploads/%s/%s", p, url.PathEscape(secret), url.PathEscape(filename))
+	req, err := c.c.NewRequest(http.MethodGet, u, nil, options)
+	if err != nil {
+		return nil, nil, err
+	}
+	buf := new(bytes.Buffer)
+	resp, err := c.c.Do(req, buf)
+	if err != nil {
+		return nil, resp, err
+	}
+	return buf.Bytes(), resp, nil
+}
+
+// ListWikis lists the wiki pages of a project.
+func (c *client) ListWikis(
+	pid interface{},
+	opt *glab.ListWikisOptions,
+	options ...glab.RequestOptionFunc,
+) ([]*glab.Wiki, *glab.Response, error) {
+	return c.c.Wikis.ListWikis(pid, opt, options...)
+}
+
+// CreateWikiPage creates a wiki page.
+func (c *client) CreateWikiPage(
+	pid interface{},
+	opt *glab.CreateWikiPageOptions,
+	options ...glab.RequestOptionFunc,
+) (*glab.Wiki, *glab.Response, error) {
+	return c.c.Wikis.CreateWikiPage(pid, opt, options...)
+}
+
+// WikiAttachment is a file uploaded to the wiki repository of a project.
+type WikiAttachment struct {
+	FileName string `json:"file_name"`
+	FilePath string `json:"file_path"`
+	Branch   string `json:"branch"`
+	Link     struct {
+		URL      string `json:"url"`
+		Markdown string `json:"markdown"`
+	} `json:"link"`
+}
+
+// UploadWikiAttachment uploads a file to the wiki repository of a project.
+func (c *client) UploadWikiAttachment(
+	pid interface{},
+	content io.Reader,
+	filename string,
+	options ...glab.RequestOptionFunc,
+) (*WikiAttachment, *glab.Response, error) {
+	p, err := projectPath(pid)
+	if err != nil {
+		return nil, nil, err
+	}
+	req, err := c.c.UploadRequest(http.MethodPost, p+"/wikis/attachments", content, filename, glab.UploadFile, nil, options)
+	if err != nil {
+		return nil, nil, err
+	}
+	a := new(WikiAttachment)
+	resp, err := c.c.Do(req, a)
+	if err != nil {
+		return nil, resp, err
+	}
+	return a, resp, nil
+}
+
+// ListSnippets lists the snippets of a project.
+func (c *client) ListSnippets(
+	pid interface{},
+	opt *glab.ListProjectSnippetsOptions,
+	options ...glab.RequestOptionFunc,
+) ([]*glab.Snippet, *glab.Response, error) {
+	return c.c.ProjectSnippets.ListSnippets(pid, opt, options...)
+}
+
+// CreateSnippet creates a project snippet.
+func (c *client) CreateSnippet(
+	pid interface{},
+	opt *glab.CreateProjectSnippetOptions,
+	options ...glab.RequestOptionFunc,
+) (*glab.Snippet, *glab.Response, error) {
+	return c.c.ProjectSnippets.CreateSnippet(pid, opt, options...)
+}
+
+// CreateSnippetNote creates a note on a project snippet.
+func (c *client) CreateSnippetNote(
+	pid interface{},
+	snippet int,
+	opt *glab.CreateSnippetNoteOptions,
+	options ...glab.RequestOptionFunc,
+) (*glab.Note, *glab.Response, error) {
+	return c.c.Notes.CreateSnippetNote(pid, snippet, opt, options...)
+}
+
+// ListSnippetDiscussions lists the discussions of a project snippet.
+func (c *client) ListSnippetDiscussions(
+	pid interface{},
+	snippet int,
+	opt *glab.ListSnippetDiscussionsOptions,
+	options ...glab.RequestOptionFunc,
+) ([]*glab.Discussion, *glab.Response, error) {
+	return c.c.Discussions.ListSnippetDiscussions(pid, snippet, opt, options...)
+}
+
+// CreateSnippetDiscussion starts a discussion on a project snippet.
+func (c *client) CreateSnippetDiscussion(
+	pid interface{},
+	snippet int,
+	opt *glab.CreateSnippetDiscussionOptions,
+	options ...glab.RequestOptionFunc,
+) (*glab.Discussion, *glab.Response, error) {
+	return c.c.Discussions.CreateSnippetDiscussion(pid, snippet, opt, options...)
+}
+
+// AddSnippetDiscussionNote adds a note to a project snippet discussion.
+func (c *client) AddSnippetDiscussionNote(
+	pid interface{},
+	snippet int,
+	discussion string,
+	opt *glab.AddSnippetDiscussionNoteOptions,
+	options ...glab.RequestOptionFunc,
+) (*glab.Note, *glab.Response, error) {
+	return c.c.Discussions.AddSnippetDiscussionNote(pid, snippet, discussion, opt, options...)
+}
+
+// ListReleases lists the releases of a project.
+func (c *client) ListReleases(
+	pid interface{},
+	opt *glab.ListReleasesOptions,
+	options ...glab.RequestOptionFunc,
+) ([]*glab.Release, *glab.Response, error) {
+	return c.c.Releases.ListReleases(pid, opt, options...)
+}
+
+// CreateRelease creates a release.
+func (c *client) CreateRelease(
+	pid interface{},
+	opt *glab.CreateReleaseOptions,
+	options ...glab.RequestOptionFunc,
+) (*glab.Release, *glab.Response, error) {
+	return c.c.Releases.CreateRelease(pid, opt, options...)
+}
+
+// releaseMilestones holds the milestones of a release, which glab.Release
+// doesn't support.
+type releaseMilestones struct {
+	Milestones []*glab.Milestone `json:"milestones"`
+}
+
+// ListReleaseMilestones returns the milestones associated with the release of
+// tag.
+func (c *client) ListReleaseMilestones(
+	pid interface{},
+	tag string,
+	options ...glab.RequestOptionFunc,
+) ([]*glab.Milestone, *glab.Response, error) {
+	p, err := projectPath(pid)
+	if err != nil {
+		return nil, nil, err
+	}
+	req, err := c.c.NewRequest(http.MethodGet, p+"/releases/"+url.PathEscape(tag), nil, options)
+	if err != nil {
+		return nil, nil, err
+	}
+	r := new(releaseMilestones)
+	resp, err := c.c.Do(req, r)
+	if err != nil {
+		return nil, resp, err
+	}
+	return r.Milestones, resp, nil
+}
+
+// ListProjectMembers lists the direct members of a project.
+func (c *client) ListProjectMembers(
+	pid interface{},
+	opt *glab.ListProjectMembersOptions,
+	options ...glab.RequestOptionFunc,
+) ([]*glab.ProjectMember, *glab.Response, error) {
+	return c.c.ProjectMembers.ListProjectMembers(pid, opt, options...)
+}
+
+// AddProjectMember adds a user to a project.
+func (c *client) AddProjectMember(
+	pid interface{},
+	opt *glab.AddProjectMemberOptions,
+	options ...glab.RequestOptionFunc,
+) (*glab.ProjectMember, *glab.Response, error) {
+	return c.c.ProjectMembers.AddProjectMember(pid, opt, options...)
+}
+
+// ListGroupEpics lists the epics of a group.
+func (c *client) ListGroupEpics(
+	gid interface{},
+	opt *glab.ListGroupEpicsOptions,
+	options ...glab.RequestOptionFunc,
+) ([]*glab.Epic, *glab.Response, error) {
+	return c.c.Epics.ListGroupEpics(gid, opt, options...)
+}
+
+// CreateEpic creates a group epic.
+func (c *client) CreateEpic(
+	gid interface{},
+	opt *glab.CreateEpicOptions,
+	options ...glab.RequestOptionFunc,
+) (*glab.Epic, *glab.Response, error) {
+	return c.c.Epics.CreateEpic(gid, opt, options...)
+}
+
+// UpdateEpic updates a group epic.
+func (c *client) UpdateEpic(
+	gid interface{},
+	epic int,
+	opt *glab.UpdateEpicOptions,
+	options ...glab.RequestOptionFunc,
+) (*glab.Epic, *glab.Response, error) {
+	return c.c.Epics.UpdateEpic(gid, epic, opt, options...)
+}
+
+// groupPath returns the API path of group gid.
+func groupPath(gid interface{}) (string, error) {
+	switch id := gid.(type) {
+	case int:
+		return fmt.Sprintf("groups/%d", id), nil
+	case string:
+		return "groups/" + url.PathEscape(id), nil
+	}
+	return "", fmt.Errorf("invalid ID type %#v, the ID must be an int or a string", gid)
+}
+
+// epicParent holds the parent of an epic, which glab.UpdateEpicOptions
+// doesn't support.
+type epicParent struct {
+	ParentID *int `url:"parent_id,omitempty" json:"parent_id,omitempty"`
+}
+
+// SetEpicParent makes the epic a child of the epic whose ID is parentID.
+func (c *client) SetEpicParent(
+	gid interface{},
+	epic int,
+	parentID int,
+	options ...glab.RequestOptionFunc,
+) (*glab.Response, error) {
+	g, err := groupPath(gid)
+	if err != nil {
+		return nil, err
+	}
+	opt := &epicParent{ParentID: &parentID}
+	req, err := c.c.NewRequest(http.MethodPut, fmt.Sprintf("%s/epics/%d", g, epic), opt, options)
+	if err != nil {
+		return nil, err
+	}
+	return c.c.Do(req, nil)
+}
+
+// ListEpicIssues lists the issues assigned to an epic.
+func (c *client) ListEpicIssues(
+	gid interface{},
+	epic int,
+	opt *glab.ListOptions,
+	options ...glab.RequestOptionFunc,
+) ([]*glab.Issue, *glab.Response, error) {
+	return c.c.EpicIssues.ListEpicIssues(gid, epic, opt, options...)
+}
+
+// AssignEpicIssue assigns an issue to an epic.
+func (c *client) AssignEpicIssue(
+	gid interface{},
+	epic int,
+	issue int,
+	options ...glab.RequestOptionFunc,
+) (*glab.EpicIssueAssignment, *glab.Response, error) {
+	return c.c.EpicIssues.AssignEpicIssue(gid, epic, issue, options...)
+}
+
+// CreateEpicNote creates a note on an epic.
+func (c *client) CreateEpicNote(
+	gid interface{},
+	epic int,
+	opt *glab.CreateEpicNoteOptions,
+	options ...glab.RequestOptionFunc,
+) (*glab.Note, *glab.Response, error) {
+	return c.c.Notes.CreateEpicNote(gid, epic, opt, options...)
+}
+
+// ListGroupEpicDiscussions lists the discussions of an epic.
+func (c *client) ListGroupEpicDiscussions(
+	gid interface{},
+	epic int,
+	opt *glab.ListGroupEpicDiscussionsOptions,
+	options ...glab.RequestOptionFunc,
+) ([]*glab.Discussion, *glab.Response, error) {
+	return c.c.Discussions.ListGroupEpicDiscussions(gid, epic, opt, options...)
+}
+
+// CreateEpicDiscussion starts a discussion on an epic.
+func (c *client) CreateEpicDiscussion(
+	gid interface{},
+	epic int,
+	opt *glab.CreateEpicDiscussionOptions,
+	options ...glab.RequestOptionFunc,
+) (*glab.Discussion, *glab.Response, error) {
+	return c.c.Discussions.CreateEpicDiscussion(gid, epic, opt, options...)
+}
+
+// AddEpicDiscussionNote adds a note to an epic discussion.
+func (c *client) AddEpicDiscussionNote(
+	gid interface{},
+	epic int,
+	discussion string,
+	opt *glab.AddEpicDiscussionNoteOptions,
+	options ...glab.RequestOptionFunc,
+) (*glab.Note, *glab.Response, error) {
+	return c.c.Discussions.AddEpicDiscussionNote(gid, epic, discussion, opt, options...)
+}
+
+// ListGroupLabels lists the labels of a group.
+func (c *client) ListGroupLabels(
+	gid interface{},
+	opt *glab.ListGroupLabelsOptions,
+	options ...glab.RequestOptionFunc,
+) ([]*glab.GroupLabel, *glab.Response, error) {
+	return c.c.GroupLabels.ListGroupLabels(gid, opt, options...)
+}
+
+// CreateGroupLabel creates a group label.
+func (c *client) CreateGroupLabel(
+	gid interface{},
+	opt *glab.CreateGroupLabelOptions,
+	options ...glab.RequestOptionFunc,
+) (*glab.GroupLabel, *glab.Response, error) {
+	return c.c.GroupLabels.CreateGroupLabel(gid, opt, options...)
+}
+
+// ListIssueBoards lists the issue boards of a project.
+func (c *client) ListIssueBoards(
+	pid interface{},
+	opt *glab.ListIssueBoardsOptions,
+	options ...glab.RequestOptionFunc,
+) ([]*glab.IssueBoard, *glab.Response, error) {
+	return c.c.Boards.ListIssueBoards(pid, opt, options...)
+}
+
+// CreateIssueBoard creates an issue board.
+func (c *client) CreateIssueBoard(
+	pid interface{},
+	opt *glab.CreateIssueBoardOptions,
+	options ...glab.RequestOptionFunc,
+) (*glab.IssueBoard, *glab.Response, error) {
+	return c.c.Boards.CreateIssueBoard(pid, opt, options...)
+}
+
+// GetIssueBoardLists lists the lists of an issue board.
+func (c *client) GetIssueBoardLists(
+	pid interface{},
+	board int,
+	opt *glab.GetIssueBoardListsOptions,
+	options ...glab.RequestOptionFunc,
+) ([]*glab.BoardList, *glab.Response, error) {
+	return c.c.Boards.GetIssueBoardLists(pid, board, opt, options...)
+}
+
+// CreateIssueBoardList creates a list in an issue board.
+func (c *client) CreateIssueBoardList(
+	pid interface{},
+	board int,
+	opt *glab.CreateIssueBoardListOptions,
+	options ...glab.RequestOptionFunc,
+) (*glab.BoardList, *glab.Response, error) {
+	return c.c.Boards.CreateIssueBoardList(pid, board, opt, options...)
+}
+
+// ListVariables lists the CI/CD variables of a project.
+func (c *client) ListVariables(
+	pid interface{},
+	opt *glab.ListProjectVariablesOptions,
+	options ...glab.RequestOptionFunc,
+) ([]*glab.ProjectVariable, *glab.Response, error) {
+	return c.c.ProjectVariables.ListVariables(pid, opt, options...)
+}
+
+// CreateVariable creates a CI/CD variable.
+func (c *client) CreateVariable(
+	pid interface{},
+	opt *glab.CreateProjectVariableOptions,
+	options ...glab.RequestOptionFunc,
+) (*glab.ProjectVariable, *glab.Response, error) {
+	return c.c.ProjectVariables.CreateVariable(pid, opt, options...)
+}
+
+// ListProjectFeatureFlags lists the feature flags of a project.
+func (c *client) ListProjectFeatureFlags(
+	pid interface{},
+	opt *glab.ListProjectFeatureFlagOptions,
+	options ...glab.RequestOptionFunc,
+) ([]*glab.ProjectFeatureFlag, *glab.Response, error) {
+	return c.c.ProjectFeatureFlags.ListProjectFeatureFlags(pid, opt, options...)
+}
+
+// CreateProjectFeatureFlag creates a feature flag.
+func (c *client) CreateProjectFeatureFlag(
+	pid interface{},
+	opt *glab.CreateProjectFeatureFlagOptions,
+	options ...glab.RequestOptionFunc,
+) (*glab.ProjectFeatureFlag, *glab.Response, error) {
+	return c.c.ProjectFeatureFlags.CreateProjectFeatureFlag(pid, opt, options...)
+}
+
+// ListEnvironments lists the environments of a project.
+func (c *client) ListEnvironments(
+	pid interface{},
+	opt *glab.ListEnvironmentsOptions,
+	options ...glab.RequestOptionFunc,
+) ([]*glab.Environment, *glab.Response, error) {
+	return c.c.Environments.ListEnvironments(pid, opt, options...)
+}
+
+// CreateEnvironment creates an environment.
+func (c *client) CreateEnvironment(
+	pid interface{},
+	opt *glab.CreateEnvironmentOptions,
+	options ...glab.RequestOptionFunc,
+) (*glab.Environment, *glab.Response, error) {
+	return c.c.Environments.CreateEnvironment(pid, opt, options...)
+}
+
+// ListFreezePeriods lists the deploy freeze periods of a project.
+func (c *client) ListFreezePeriods(
+	pid interface{},
+	opt *glab.ListFreezePeriodsOptions,
+	options ...glab.RequestOptionFunc,
+) ([]*glab.FreezePeriod, *glab.Response, error) {
+	return c.c.FreezePeriods.ListFreezePeriods(pid, opt, options...)
+}
+
+// CreateFreezePeriod creates a deploy freeze period.
+func (c *client) CreateFreezePeriod(
+	pid interface{},
+	opt *glab.CreateFreezePeriodOptions,
+	options ...glab.RequestOptionFunc,
+) (*glab.FreezePeriod, *glab.Response, error) {
+	return c.c.FreezePeriods.CreateFreezePeriodOptions(pid, opt, options...)
+}
+
+// ListProjectBadges lists the badges of a project, including its group
+// badges.
+func (c *client) ListProjectBadges(
+	pid interface{},
+	opt *glab.ListProjectBadgesOptions,
+	options ...glab.RequestOptionFunc,
+) ([]*glab.ProjectBadge, *glab.Response, error) {
+	return c.c.ProjectBadges.ListProjectBadges(pid, opt, options...)
+}
+
+// AddProjectBadge adds a badge to a project.
+func (c *client) AddProjectBadge(
+	pid interface{},
+	opt *glab.AddProjectBadgeOptions,
+	options ...glab.RequestOptionFunc,
+) (*glab.ProjectBadge, *glab.Response, error) {
+	return c.c.ProjectBadges.AddProjectBadge(pid, opt, options...)
+}
+
+// ListProjectHooks lists the webhooks of a project.
+func (c *client) ListProjectHooks(
+	pid interface{},
+	opt *glab.ListProjectHooksOptions,
+	options ...glab.RequestOptionFunc,
+) ([]*glab.ProjectHook, *glab.Response, error) {
+	return c.c.Projects.ListProjectHooks(pid, opt, options...)
+}
+
+// AddProjectHook adds a webhook to a project.
+func (c *client) AddProjectHook(
+	pid interface{},
+	opt *glab.AddProjectHookOptions,
+	options ...glab.RequestOptionFunc,
+) (*glab.ProjectHook, *glab.Response, error) {
+	return c.c.Projects.AddProjectHook(pid, opt, options...)
+}
+
+// ListProtectedBranches lists the protected branches of a project.
+func (c *client) ListProtectedBranches(
+	pid interface{},
+	opt *glab.ListProtectedBranchesOptions,
+	options ...glab.RequestOptionFunc,
+) ([]*glab.ProtectedBranch, *glab.Response, error) {
+	return c.c.ProtectedBranches.ListProtectedBranches(pid, opt, options...)
+}
+
+// branchAccessDeployKey holds the deploy key of a protected branch access
+// level entry, which glab.BranchAccessDescription doesn't support (such an
+// entry is decoded as a bare Maintainer role).
+type branchAccessDeployKey struct {
+	ID          int  `json:"id"`
+	DeployKeyID *int `json:"deploy_key_id"`
+}
+
+// protectedBranchDeployKeys holds the access level entries of a protected
+// branch, along with their deploy key, if any.
+type protectedBranchDeployKeys struct {
+	PushAccessLevels      []*branchAccessDeployKey `json:"push_access_levels"`
+	MergeAccessLevels     []*branchAccessDeployKey `json:"merge_access_levels"`
+	UnprotectAccessLevels []*branchAccessDeployKey `json:"unprotect_access_levels"`
+}
+
+// ListProtectedBranchDeployKeys returns the deploy keys allowed by the
+// protected branches of a project, by access level entry ID.
+func (c *client) ListProtectedBranchDeployKeys(
+	pid interface{},
+	opt *glab.ListProtectedBranchesOptions,
+	options ...glab.RequestOptionFunc,
+) (map[int]int, *glab.Response, error) {
+	p, err := projectPath(pid)
+	if err != nil {
+		return nil, nil, err
+	}
+	req, err := c.c.NewRequest(http.MethodGet, p+"/protected_branches", opt, options)
+	if err != nil {
+		return nil, nil, err
+	}
+	var pbs []*protectedBranchDeployKeys
+	resp, err := c.c.Do(req, &pbs)
+	if err != nil {
+		return nil, resp, err
+	}
+	keys := make(map[int]int)
+	for _, pb := range pbs {
+		for _, ls := range [][]*branchAccessDeployKey{pb.PushAccessLevels, pb.MergeAccessLevels, pb.UnprotectAccessLevels} {
+			for _, l := range ls {
+				if l.DeployKeyID != nil {
+					keys[l.ID] = *l.DeployKeyID
+				}
+			}
+		}
+	}
+	return keys, resp, nil
+}
+
+// ProtectRepositoryBranches protects a branch, or the branches matching a
+// wildcard.
+func (c *client) ProtectRepositoryBranches(
+	pid interface{},
+	opt *glab.ProtectRepositoryBranchesOptions,
+	options ...glab.RequestOptionFunc,
+) (*glab.ProtectedBranch, *glab.Response, error) {
+	return c.c.ProtectedBranches.ProtectRepositoryBranches(pid, opt, options...)
+}
+
+// UnprotectRepositoryBranches removes the protection of a branch, or of the
+// branches matching a wildcard.
+func (c *client) UnprotectRepositoryBranches(
+	pid interface{},
+	branch string,
+	options ...glab.RequestOptionFunc,
+) (*glab.Response, error) {
+	return c.c.ProtectedBranches.UnprotectRepositoryBranches(pid, branch, options...)
+}
+
+// ListProtectedTags lists the protected tags of a project.
+func (c *client) ListProtectedTags(
+	pid interface{},
+	opt *glab.ListProtectedTagsOptions,
+	options ...glab.RequestOptionFunc,
+) ([]*glab.ProtectedTag, *glab.Response, error) {
+	return c.c.ProtectedTags.ListProtectedTags(pid, opt, options...)
+}
+
+// ProtectRepositoryTags protects a tag, or the tags matching a wildcard.
+func (c *client) ProtectRepositoryTags(
+	pid interface{},
+	opt *glab.ProtectRepositoryTagsOptions,
+	options ...glab.RequestOptionFunc,
+) (*glab.ProtectedTag, *glab.Response, error) {
+	return c.c.ProtectedTags.ProtectRepositoryTags(pid, opt, options...)
+}
+
+// UnprotectRepositoryTags removes the protection of a tag, or of the tags
+// matching a wildcard.
+func (c *client) UnprotectRepositoryTags(
+	pid interface{},
+	tag string,
+	options ...glab.RequestOptionFunc,
+) (*glab.Response, error) {
+	return c.c.ProtectedTags.UnprotectRepositoryTags(pid, tag, options...)
+}
+
+// GetProjectApprovalRules lists the project-level approval rules. Unlike
+// go-gitlab's, opt is sent so that pages can be fetched.
+func (c *client) GetProjectApprovalRules(
+	pid interface{},
+	opt *glab.GetProjectApprovalRulesListsOptions,
+	options ...glab.RequestOptionFunc,
+) ([]*glab.ProjectApprovalRule, *glab.Response, error) {
+	p, err := projectPath(pid)
+	if err != nil {
+		return nil, nil, err
+	}
+	req, err := c.c.NewRequest(http.MethodGet, p+"/approval_rules", opt, options)
+	if err != nil {
+		return nil, nil, err
+	}
+	var rs []*glab.ProjectApprovalRule
+	resp, err := c.c.Do(req, &rs)
+	if err != nil {
+		return nil, resp, err
+	}
+	return rs, resp, nil
+}
+
+// CreateProjectApprovalRule creates a project-level approval rule.
+func (c *client) CreateProjectApprovalRule(
+	pid interface{},
+	opt *glab.CreateProjectLevelRuleOptions,
+	options ...glab.RequestOptionFunc,
+) (*glab.ProjectApprovalRule, *glab.Response, error) {
+	return c.c.Projects.CreateProjectApprovalRule(pid, opt, options...)
+}
+
+// GetBranch returns a repository branch.
+func (c *client) GetBranch(
+	pid interface{},
+	branch string,
+	options ...glab.RequestOptionFunc,
+) (*glab.Branch, *glab.Response, error) {
+	return c.c.Branches.GetBranch(pid, branch, options...)
+}
+
+// GetTag returns a repository tag.
+func (c *client) GetTag(
+	pid interface{},
+	tag string,
+	options ...glab.RequestOptionFunc,
+) (*glab.Tag, *glab.Response, error) {
+	return c.c.Tags.GetTag(pid, tag, options...)
+}
+
 // BaseURL returns the base URL used.
 func (c *client) BaseURL() *url.URL {
 	return c.c.BaseURL()
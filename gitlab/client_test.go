@@ -0,0 +1,110 @@
+package gitlab
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	glab "github.com/xanzy/go-gitlab"
+)
+
+func TestDownloadFile(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/uploads/public/shot.png", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		w.Write([]byte("PNG"))
+	})
+	mux.HandleFunc("/uploads/stored/shot.png", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/bucket/shot.png", http.StatusFound)
+	})
+	mux.HandleFunc("/bucket/shot.png", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		w.Write([]byte("PNG"))
+	})
+	mux.HandleFunc("/uploads/private/shot.png", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/users/sign_in", http.StatusFound)
+	})
+	mux.HandleFunc("/users/sign_in", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte("<html>Sign in</html>"))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	c, err := NewClient().WithToken("token", glab.WithBaseURL(srv.URL))
+	require.NoError(t, err)
+
+	runs := []struct {
+		name, path string
+		ok         bool
+	}{
+		{"File", "/uploads/public/shot.png", true},
+		{"Redirect to object storage", "/uploads/stored/shot.png", true},
+		{"Redirect to sign-in page", "/uploads/private/shot.png", false},
+	}
+	for _, run := range runs {
+		t.Run(run.name, func(t *testing.T) {
+			data, _, err := c.DownloadFile(srv.URL + run.path)
+			if !run.ok {
+				assert.ErrorIs(t, err, errNotAFile)
+				assert.Nil(t, data)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, []byte("PNG"), data)
+		})
+	}
+}
+
+func TestGetIssueWithHealthStatus(t *testing.T) {
+	calls := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v4/projects/1/issues/1", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id": 11, "iid": 1, "title": "issue1", "labels": ["bug"], "health_status": "at_risk"}`))
+	})
+	mux.HandleFunc("/api/v4/projects/1/issues/2", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id": 12, "iid": 2, "title": "issue2"}`))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	c, err := NewClient().WithToken("token", glab.WithBaseURL(srv.URL))
+	require.NoError(t, err)
+
+	issue, health, _, err := c.GetIssueWithHealthStatus(1, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "issue1", issue.Title)
+	assert.Equal(t, glab.Labels{"bug"}, issue.Labels)
+	assert.Equal(t, "at_risk", health)
+	assert.Equal(t, 1, calls)
+
+	issue, health, _, err = c.GetIssueWithHealthStatus(1, 2)
+	require.NoError(t, err)
+	assert.Equal(t, "issue2", issue.Title)
+	assert.Empty(t, health)
+}
+
+func TestDownloadUpload(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v4/projects/1/uploads/66dbcd21ec5d24ed6ea225176098d52b/log file.txt", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte("log"))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	c, err := NewClient().WithToken("token", glab.WithBaseURL(srv.URL))
+	require.NoError(t, err)
+
+	data, _, err := c.DownloadUpload(1, "66dbcd21ec5d24ed6ea225176098d52b", "log file.txt")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("log"), data)
+
+	_, _, err = c.DownloadUpload(1, "66dbcd21ec5d24ed6ea225176098d52b", "other.txt")
+	assert.Error(t, err)
+}
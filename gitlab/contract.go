@@ -1,6 +1,7 @@
 package gitlab
 
 import (
+	"io"
 	"net/url"
 
 	glab "github.com/xanzy/go-gitlab"
@@ -24,9 +25,12 @@ type GitLaber interface {
 	GitLab() *glab.Client
 	// Project
 	GetProject(interface{}, *glab.GetProjectOptions, ...glab.RequestOptionFunc) (*glab.Project, *glab.Response, error)
+	EditProject(interface{}, *glab.EditProjectOptions, ...glab.RequestOptionFunc) (*glab.Project, *glab.Response, error)
 	// Labels
 	ListLabels(interface{}, *glab.ListLabelsOptions, ...glab.RequestOptionFunc) ([]*glab.Label, *glab.Response, error)
 	CreateLabel(interface{}, *glab.CreateLabelOptions, ...glab.RequestOptionFunc) (*glab.Label, *glab.Response, error)
+	UpdateLabel(interface{}, *glab.UpdateLabelOptions, ...glab.RequestOptionFunc) (*glab.Label, *glab.Response, error)
+	ListLabelPriorities(interface{}, *glab.ListLabelsOptions, ...glab.RequestOptionFunc) (map[string]int, *glab.Response, error)
 	// Milestones
 	ListMilestones(interface{}, *glab.ListMilestonesOptions, ...glab.RequestOptionFunc) ([]*glab.Milestone, *glab.Response, error)
 	CreateMilestone(interface{}, *glab.CreateMilestoneOptions, ...glab.RequestOptionFunc) (*glab.Milestone, *glab.Response, error)
@@ -37,9 +41,117 @@ type GitLaber interface {
 	CreateIssue(interface{}, *glab.CreateIssueOptions, ...glab.RequestOptionFunc) (*glab.Issue, *glab.Response, error)
 	UpdateIssue(interface{}, int, *glab.UpdateIssueOptions, ...glab.RequestOptionFunc) (*glab.Issue, *glab.Response, error)
 	DeleteIssue(interface{}, int, ...glab.RequestOptionFunc) (*glab.Response, error)
+	GetIssueWithHealthStatus(interface{}, int, ...glab.RequestOptionFunc) (*glab.Issue, string, *glab.Response, error)
+	SetIssueHealthStatus(interface{}, int, string, ...glab.RequestOptionFunc) (*glab.Response, error)
+	SetTimeEstimate(interface{}, int, *glab.SetTimeEstimateOptions, ...glab.RequestOptionFunc) (*glab.TimeStats, *glab.Response, error)
+	AddSpentTime(interface{}, int, *glab.AddSpentTimeOptions, ...glab.RequestOptionFunc) (*glab.TimeStats, *glab.Response, error)
+	// Issue links
+	ListIssueRelations(interface{}, int, ...glab.RequestOptionFunc) ([]*glab.IssueRelation, *glab.Response, error)
+	CreateIssueLink(interface{}, int, *glab.CreateIssueLinkOptions, ...glab.RequestOptionFunc) (*glab.IssueLink, *glab.Response, error)
 	// Users
 	ListUsers(*glab.ListUsersOptions, ...glab.RequestOptionFunc) ([]*glab.User, *glab.Response, error)
+	GetUser(int, glab.GetUsersOptions, ...glab.RequestOptionFunc) (*glab.User, *glab.Response, error)
+	// Groups
+	GetGroup(interface{}, *glab.GetGroupOptions, ...glab.RequestOptionFunc) (*glab.Group, *glab.Response, error)
 	// Notes
 	ListIssueNotes(interface{}, int, *glab.ListIssueNotesOptions, ...glab.RequestOptionFunc) ([]*glab.Note, *glab.Response, error)
 	CreateIssueNote(interface{}, int, *glab.CreateIssueNoteOptions, ...glab.RequestOptionFunc) (*glab.Note, *glab.Response, error)
+	// Merge requests
+	ListProjectMergeRequests(interface{}, *glab.ListProjectMergeRequestsOptions, ...glab.RequestOptionFunc) ([]*glab.MergeRequest, *glab.Response, error)
+	GetMergeRequest(interface{}, int, *glab.GetMergeRequestsOptions, ...glab.RequestOptionFunc) (*glab.MergeRequest, *glab.Response, error)
+	CreateMergeRequest(interface{}, *glab.CreateMergeRequestOptions, ...glab.RequestOptionFunc) (*glab.MergeRequest, *glab.Response, error)
+	UpdateMergeRequest(interface{}, int, *glab.UpdateMergeRequestOptions, ...glab.RequestOptionFunc) (*glab.MergeRequest, *glab.Response, error)
+	CreateMergeRequestNote(interface{}, int, *glab.CreateMergeRequestNoteOptions, ...glab.RequestOptionFunc) (*glab.Note, *glab.Response, error)
+	// Discussions
+	ListIssueDiscussions(interface{}, int, *glab.ListIssueDiscussionsOptions, ...glab.RequestOptionFunc) ([]*glab.Discussion, *glab.Response, error)
+	CreateIssueDiscussion(interface{}, int, *glab.CreateIssueDiscussionOptions, ...glab.RequestOptionFunc) (*glab.Discussion, *glab.Response, error)
+	AddIssueDiscussionNote(interface{}, int, string, *glab.AddIssueDiscussionNoteOptions, ...glab.RequestOptionFunc) (*glab.Note, *glab.Response, error)
+	ListMergeRequestDiscussions(interface{}, int, *glab.ListMergeRequestDiscussionsOptions, ...glab.RequestOptionFunc) ([]*glab.Discussion, *glab.Response, error)
+	CreateMergeRequestDiscussion(interface{}, int, *glab.CreateMergeRequestDiscussionOptions, ...glab.RequestOptionFunc) (*glab.Discussion, *glab.Response, error)
+	AddMergeRequestDiscussionNote(interface{}, int, string, *glab.AddMergeRequestDiscussionNoteOptions, ...glab.RequestOptionFunc) (*glab.Note, *glab.Response, error)
+	ResolveMergeRequestDiscussion(interface{}, int, string, *glab.ResolveMergeRequestDiscussionOptions, ...glab.RequestOptionFunc) (*glab.Discussion, *glab.Response, error)
+	// Award emoji
+	ListIssueAwardEmoji(interface{}, int, *glab.ListAwardEmojiOptions, ...glab.RequestOptionFunc) ([]*glab.AwardEmoji, *glab.Response, error)
+	CreateIssueAwardEmoji(interface{}, int, *glab.CreateAwardEmojiOptions, ...glab.RequestOptionFunc) (*glab.AwardEmoji, *glab.Response, error)
+	ListIssuesAwardEmojiOnNote(interface{}, int, int, *glab.ListAwardEmojiOptions, ...glab.RequestOptionFunc) ([]*glab.AwardEmoji, *glab.Response, error)
+	CreateIssuesAwardEmojiOnNote(interface{}, int, int, *glab.CreateAwardEmojiOptions, ...glab.RequestOptionFunc) (*glab.AwardEmoji, *glab.Response, error)
+	// Resource events
+	ListIssueLabelEvents(interface{}, int, *glab.ListLabelEventsOptions, ...glab.RequestOptionFunc) ([]*glab.LabelEvent, *glab.Response, error)
+	ListIssueMilestoneEvents(interface{}, int, *glab.ListMilestoneEventsOptions, ...glab.RequestOptionFunc) ([]*glab.MilestoneEvent, *glab.Response, error)
+	ListIssueStateEvents(interface{}, int, *glab.ListStateEventsOptions, ...glab.RequestOptionFunc) ([]*glab.StateEvent, *glab.Response, error)
+	ListIssueWeightEvents(interface{}, int, *glab.ListWeightEventsOptions, ...glab.RequestOptionFunc) ([]*glab.WeightEvent, *glab.Response, error)
+	// Uploads
+	UploadFile(interface{}, io.Reader, string, ...glab.RequestOptionFunc) (*glab.ProjectFile, *glab.Response, error)
+	DownloadFile(string, ...glab.RequestOptionFunc) ([]byte, *glab.Response, error)
+	DownloadUpload(interface{}, string, string, ...glab.RequestOptionFunc) ([]byte, *glab.Response, error)
+	// Wikis
+	ListWikis(interface{}, *glab.ListWikisOptions, ...glab.RequestOptionFunc) ([]*glab.Wiki, *glab.Response, error)
+	CreateWikiPage(interface{}, *glab.CreateWikiPageOptions, ...glab.RequestOptionFunc) (*glab.Wiki, *glab.Response, error)
+	UploadWikiAttachment(interface{}, io.Reader, string, ...glab.RequestOptionFunc) (*WikiAttachment, *glab.Response, error)
+	// Snippets
+	ListSnippets(interface{}, *glab.ListProjectSnippetsOptions, ...glab.RequestOptionFunc) ([]*glab.Snippet, *glab.Response, error)
+	CreateSnippet(interface{}, *glab.CreateProjectSnippetOptions, ...glab.RequestOptionFunc) (*glab.Snippet, *glab.Response, error)
+	CreateSnippetNote(interface{}, int, *glab.CreateSnippetNoteOptions, ...glab.RequestOptionFunc) (*glab.Note, *glab.Response, error)
+	ListSnippetDiscussions(interface{}, int, *glab.ListSnippetDiscussionsOptions, ...glab.RequestOptionFunc) ([]*glab.Discussion, *glab.Response, error)
+	CreateSnippetDiscussion(interface{}, int, *glab.CreateSnippetDiscussionOptions, ...glab.RequestOptionFunc) (*glab.Discussion, *glab.Response, error)
+	AddSnippetDiscussionNote(interface{}, int, string, *glab.AddSnippetDiscussionNoteOptions, ...glab.RequestOptionFunc) (*glab.Note, *glab.Response, error)
+	// Releases
+	ListReleases(interface{}, *glab.ListReleasesOptions, ...glab.RequestOptionFunc) ([]*glab.Release, *glab.Response, error)
+	CreateRelease(interface{}, *glab.CreateReleaseOptions, ...glab.RequestOptionFunc) (*glab.Release, *glab.Response, error)
+	ListReleaseMilestones(interface{}, string, ...glab.RequestOptionFunc) ([]*glab.Milestone, *glab.Response, error)
+	// Members
+	ListProjectMembers(interface{}, *glab.ListProjectMembersOptions, ...glab.RequestOptionFunc) ([]*glab.ProjectMember, *glab.Response, error)
+	AddProjectMember(interface{}, *glab.AddProjectMemberOptions, ...glab.RequestOptionFunc) (*glab.ProjectMember, *glab.Response, error)
+	// Epics
+	ListGroupEpics(interface{}, *glab.ListGroupEpicsOptions, ...glab.RequestOptionFunc) ([]*glab.Epic, *glab.Response, error)
+	CreateEpic(interface{}, *glab.CreateEpicOptions, ...glab.RequestOptionFunc) (*glab.Epic, *glab.Response, error)
+	UpdateEpic(interface{}, int, *glab.UpdateEpicOptions, ...glab.RequestOptionFunc) (*glab.Epic, *glab.Response, error)
+	SetEpicParent(interface{}, int, int, ...glab.RequestOptionFunc) (*glab.Response, error)
+	ListEpicIssues(interface{}, int, *glab.ListOptions, ...glab.RequestOptionFunc) ([]*glab.Issue, *glab.Response, error)
+	AssignEpicIssue(interface{}, int, int, ...glab.RequestOptionFunc) (*glab.EpicIssueAssignment, *glab.Response, error)
+	CreateEpicNote(interface{}, int, *glab.CreateEpicNoteOptions, ...glab.RequestOptionFunc) (*glab.Note, *glab.Response, error)
+	ListGroupEpicDiscussions(interface{}, int, *glab.ListGroupEpicDiscussionsOptions, ...glab.RequestOptionFunc) ([]*glab.Discussion, *glab.Response, error)
+	CreateEpicDiscussion(interface{}, int, *glab.CreateEpicDiscussionOptions, ...glab.RequestOptionFunc) (*glab.Discussion, *glab.Response, error)
+	AddEpicDiscussionNote(interface{}, int, string, *glab.AddEpicDiscussionNoteOptions, ...glab.RequestOptionFunc) (*glab.Note, *glab.Response, error)
+	// Group labels
+	ListGroupLabels(interface{}, *glab.ListGroupLabelsOptions, ...glab.RequestOptionFunc) ([]*glab.GroupLabel, *glab.Response, error)
+	CreateGroupLabel(interface{}, *glab.CreateGroupLabelOptions, ...glab.RequestOptionFunc) (*glab.GroupLabel, *glab.Response, error)
+	// Boards
+	ListIssueBoards(interface{}, *glab.ListIssueBoardsOptions, ...glab.RequestOptionFunc) ([]*glab.IssueBoard, *glab.Response, error)
+	CreateIssueBoard(interface{}, *glab.CreateIssueBoardOptions, ...glab.RequestOptionFunc) (*glab.IssueBoard, *glab.Response, error)
+	GetIssueBoardLists(interface{}, int, *glab.GetIssueBoardListsOptions, ...glab.RequestOptionFunc) ([]*glab.BoardList, *glab.Response, error)
+	CreateIssueBoardList(interface{}, int, *glab.CreateIssueBoardListOptions, ...glab.RequestOptionFunc) (*glab.BoardList, *glab.Response, error)
+	// CI/CD variables
+	ListVariables(interface{}, *glab.ListProjectVariablesOptions, ...glab.RequestOptionFunc) ([]*glab.ProjectVariable, *glab.Response, error)
+	CreateVariable(interface{}, *glab.CreateProjectVariableOptions, ...glab.RequestOptionFunc) (*glab.ProjectVariable, *glab.Response, error)
+	// Feature flags
+	ListProjectFeatureFlags(interface{}, *glab.ListProjectFeatureFlagOptions, ...glab.RequestOptionFunc) ([]*glab.ProjectFeatureFlag, *glab.Response, error)
+	CreateProjectFeatureFlag(interface{}, *glab.CreateProjectFeatureFlagOptions, ...glab.RequestOptionFunc) (*glab.ProjectFeatureFlag, *glab.Response, error)
+	// Environments
+	ListEnvironments(interface{}, *glab.ListEnvironmentsOptions, ...glab.RequestOptionFunc) ([]*glab.Environment, *glab.Response, error)
+	CreateEnvironment(interface{}, *glab.CreateEnvironmentOptions, ...glab.RequestOptionFunc) (*glab.Environment, *glab.Response, error)
+	// Freeze periods
+	ListFreezePeriods(interface{}, *glab.ListFreezePeriodsOptions, ...glab.RequestOptionFunc) ([]*glab.FreezePeriod, *glab.Response, error)
+	CreateFreezePeriod(interface{}, *glab.CreateFreezePeriodOptions, ...glab.RequestOptionFunc) (*glab.FreezePeriod, *glab.Response, error)
+	// Badges
+	ListProjectBadges(interface{}, *glab.ListProjectBadgesOptions, ...glab.RequestOptionFunc) ([]*glab.ProjectBadge, *glab.Response, error)
+	AddProjectBadge(interface{}, *glab.AddProjectBadgeOptions, ...glab.RequestOptionFunc) (*glab.ProjectBadge, *glab.Response, error)
+	// Hooks
+	ListProjectHooks(interface{}, *glab.ListProjectHooksOptions, ...glab.RequestOptionFunc) ([]*glab.ProjectHook, *glab.Response, error)
+	AddProjectHook(interface{}, *glab.AddProjectHookOptions, ...glab.RequestOptionFunc) (*glab.ProjectHook, *glab.Response, error)
+	// Protected branches and tags
+	ListProtectedBranches(interface{}, *glab.ListProtectedBranchesOptions, ...glab.RequestOptionFunc) ([]*glab.ProtectedBranch, *glab.Response, error)
+	ListProtectedBranchDeployKeys(interface{}, *glab.ListProtectedBranchesOptions, ...glab.RequestOptionFunc) (map[int]int, *glab.Response, error)
+	ProtectRepositoryBranches(interface{}, *glab.ProtectRepositoryBranchesOptions, ...glab.RequestOptionFunc) (*glab.ProtectedBranch, *glab.Response, error)
+	UnprotectRepositoryBranches(interface{}, string, ...glab.RequestOptionFunc) (*glab.Response, error)
+	ListProtectedTags(interface{}, *glab.ListProtectedTagsOptions, ...glab.RequestOptionFunc) ([]*glab.ProtectedTag, *glab.Response, error)
+	ProtectRepositoryTags(interface{}, *glab.ProtectRepositoryTagsOptions, ...glab.RequestOptionFunc) (*glab.ProtectedTag, *glab.Response, error)
+	UnprotectRepositoryTags(interface{}, string, ...glab.RequestOptionFunc) (*glab.Response, error)
+	// Approval rules
+	GetProjectApprovalRules(interface{}, *glab.GetProjectApprovalRulesListsOptions, ...glab.RequestOptionFunc) ([]*glab.ProjectApprovalRule, *glab.Response, error)
+	CreateProjectApprovalRule(interface{}, *glab.CreateProjectLevelRuleOptions, ...glab.RequestOptionFunc) (*glab.ProjectApprovalRule, *glab.Response, error)
+	// Branches
+	GetBranch(interface{}, string, ...glab.RequestOptionFunc) (*glab.Branch, *glab.Response, error)
+	// Tags
+	GetTag(interface{}, string, ...glab.RequestOptionFunc) (*glab.Tag, *glab.Response, error)
 }
@@ -0,0 +1,139 @@
+package migration
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gotsunami/gitlab-copy/gitlab"
+	glab "github.com/xanzy/go-gitlab"
+)
+
+// listApprovalRules returns all project-level approval rules of project pid,
+// fetching all pages.
+func listApprovalRules(c gitlab.GitLaber, pid int) ([]*glab.ProjectApprovalRule, error) {
+	all := make([]*glab.ProjectApprovalRule, 0)
+	opts := &glab.GetProjectApprovalRulesListsOptions{PerPage: ResultsPerPage, Page: 1}
+	for {
+		rs, _, err := c.GetProjectApprovalRules(pid, opts)
+		if err != nil {
+			return nil, err
+		}
+		if len(rs) == 0 {
+			break
+		}
+		all = append(all, rs...)
+		opts.Page++
+	}
+	return all, nil
+}
+
+// migrateApprovalRule creates the source approval rule r on target, with its
+// users, groups and protected branches resolved against the target.
+// branches maps the target protected branch names to their IDs.
+func (m *Migration) migrateApprovalRule(r *glab.ProjectApprovalRule, branches map[string]int) error {
+	missing := make([]string, 0)
+	users := make([]int, 0)
+	for _, u := range r.Users {
+		uid, err := m.targetUserID(u.Username)
+		if err != nil {
+			return err
+		}
+		if uid == nil {
+			missing = append(missing, "@"+u.Username)
+			continue
+		}
+		users = append(users, *uid)
+	}
+	groups := make([]int, 0)
+	for _, g := range r.Groups {
+		gid, err := m.targetGroup(g.FullPath)
+		if err != nil {
+			return err
+		}
+		if gid == nil {
+			missing = append(missing, "group "+g.FullPath)
+			continue
+		}
+		groups = append(groups, *gid)
+	}
+	pbs := make([]int, 0)
+	for _, pb := range r.ProtectedBranches {
+		id, ok := branches[pb.Name]
+		if !ok {
+			missing = append(missing, "branch "+pb.Name)
+			continue
+		}
+		pbs = append(pbs, id)
+	}
+	// A rule without any branch applies to all of them.
+	if len(r.ProtectedBranches) > 0 && len(pbs) == 0 {
+		fmt.Printf("target: approval rule %s: none of its protected branches exists on target, skipping...\n", r.Name)
+		return nil
+	}
+
+	ropts := &glab.CreateProjectLevelRuleOptions{
+		Name:                          &r.Name,
+		ApprovalsRequired:             &r.ApprovalsRequired,
+		UserIDs:                       &users,
+		GroupIDs:                      &groups,
+		ProtectedBranchIDs:            &pbs,
+		AppliesToAllProtectedBranches: &r.AppliesToAllProtectedBranches,
+	}
+	if r.RuleType != "" {
+		ropts.RuleType = &r.RuleType
+	}
+	if _, _, err := m.Endpoint.DstClient.CreateProjectApprovalRule(m.dstProject.ID, ropts); err != nil {
+		return fmt.Errorf("target: error creating approval rule '%s': %s", r.Name, err.Error())
+	}
+	fmt.Printf("target: created approval rule %s\n", r.Name)
+	if len(missing) > 0 {
+		fmt.Printf("target: approval rule %s: no match for %s, not added\n", r.Name, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// migrateApprovalRules copies the source project-level merge request approval
+// rules which don't exist on target yet (by name). Approvers are matched by
+// username and group path, protected branches by name.
+func (m *Migration) migrateApprovalRules() error {
+	source := m.Endpoint.SrcClient
+	target := m.Endpoint.DstClient
+
+	srcProjectID := m.srcProject.ID
+	tarProjectID := m.dstProject.ID
+
+	fmt.Println("Copying approval rules ...")
+	rules, err := listApprovalRules(source, srcProjectID)
+	if err != nil {
+		return fmt.Errorf("source: can't fetch approval rules: %s", err.Error())
+	}
+	fmt.Printf("Found %d approval rules\n", len(rules))
+	trs, err := listApprovalRules(target, tarProjectID)
+	if err != nil {
+		return fmt.Errorf("target: can't fetch approval rules: %s", err.Error())
+	}
+	existing := make(map[string]bool)
+	for _, r := range trs {
+		existing[r.Name] = true
+	}
+
+	tpbs, err := listProtectedBranches(target, tarProjectID)
+	if err != nil {
+		return fmt.Errorf("target: can't fetch protected branches: %s", err.Error())
+	}
+	branches := make(map[string]int)
+	for _, pb := range tpbs {
+		branches[pb.Name] = pb.ID
+	}
+
+	for _, r := range rules {
+		if existing[r.Name] {
+			fmt.Printf("target: approval rule %s already exists, skipping...\n", r.Name)
+			continue
+		}
+		if err := m.migrateApprovalRule(r, branches); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package migration
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/gotsunami/gitlab-copy/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	glab "github.com/xanzy/go-gitlab"
+)
+
+func TestMigrateApprovalRules(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	runs := []struct {
+		name    string                     // Sub-test name
+		config  string                     // YAML config
+		setup   func(src, dst *fakeClient) // Defines any option before calling Migrate()
+		asserts func(err error, src, dst *fakeClient)
+	}{
+		{
+			"Listing source approval rules fails",
+			cfg16,
+			func(src, dst *fakeClient) {
+				src.errors.listApprovalRules = errors.New("err")
+			},
+			func(err error, src, dst *fakeClient) {
+				assert.Error(err)
+			},
+		},
+		{
+			"Creating approval rule fails",
+			cfg16,
+			func(src, dst *fakeClient) {
+				src.approvalRules = []*glab.ProjectApprovalRule{{Name: "Security"}}
+				dst.errors.createApprovalRule = errors.New("err")
+			},
+			func(err error, src, dst *fakeClient) {
+				assert.Error(err)
+			},
+		},
+		{
+			"Rules already existing on target are skipped",
+			cfg16,
+			func(src, dst *fakeClient) {
+				src.approvalRules = []*glab.ProjectApprovalRule{
+					{Name: "All Members", RuleType: "any_approver", ApprovalsRequired: 1},
+					{Name: "Security", ApprovalsRequired: 2},
+				}
+				dst.approvalRules = []*glab.ProjectApprovalRule{{Name: "All Members"}}
+			},
+			func(err error, src, dst *fakeClient) {
+				require.NoError(err)
+				if assert.Len(dst.approvalRules, 2) {
+					assert.Equal("Security", dst.approvalRules[1].Name)
+					assert.Equal(2, dst.approvalRules[1].ApprovalsRequired)
+				}
+			},
+		},
+		{
+			"Approvers and protected branches resolved on target",
+			cfg16,
+			func(src, dst *fakeClient) {
+				dst.users = []*glab.User{{ID: 10, Username: "alice"}}
+				dst.groups = []*glab.Group{{ID: 30, FullPath: "dest/security"}}
+				src.protectedBranches = []*glab.ProtectedBranch{{Name: "main"}, {Name: "stable"}}
+				src.approvalRules = []*glab.ProjectApprovalRule{
+					{
+						Name:              "Security",
+						RuleType:          "regular",
+						ApprovalsRequired: 2,
+						Users:             []*glab.BasicUser{{Username: "alice"}, {Username: "bob"}},
+						Groups: []*glab.Group{
+							{FullPath: "source/security"},
+							{FullPath: "other"},
+						},
+						ProtectedBranches: []*glab.ProtectedBranch{{Name: "main"}, {Name: "legacy"}},
+					},
+				}
+			},
+			func(err error, src, dst *fakeClient) {
+				require.NoError(err)
+				require.Len(dst.approvalRules, 1)
+				r := dst.approvalRules[0]
+				assert.Equal("regular", r.RuleType)
+				assert.Equal(2, r.ApprovalsRequired)
+				if assert.Len(r.Users, 1) {
+					assert.Equal(10, r.Users[0].ID)
+				}
+				if assert.Len(r.Groups, 1) {
+					assert.Equal(30, r.Groups[0].ID)
+				}
+				if assert.Len(r.ProtectedBranches, 1) {
+					assert.Equal("main", r.ProtectedBranches[0].Name)
+				}
+			},
+		},
+		{
+			"Rule skipped when none of its protected branches exists on target",
+			cfg16,
+			func(src, dst *fakeClient) {
+				src.approvalRules = []*glab.ProjectApprovalRule{
+					{
+						Name:              "Release",
+						ApprovalsRequired: 1,
+						ProtectedBranches: []*glab.ProtectedBranch{{Name: "legacy"}},
+					},
+				}
+			},
+			func(err error, src, dst *fakeClient) {
+				require.NoError(err)
+				assert.Empty(dst.approvalRules)
+			},
+		},
+	}
+	for _, run := range runs {
+		t.Run(run.name, func(t *testing.T) {
+			conf, err := config.Parse(strings.NewReader(run.config))
+			require.NoError(err)
+			// Load the conf.
+			m, err := New(conf)
+			require.NoError(err)
+			_, err = m.SourceProject(m.params.SrcPrj.Name)
+			require.NoError(err)
+			_, err = m.DestProject(m.params.DstPrj.Name)
+			require.NoError(err)
+			// Setup.
+			run.setup(source(m), dest(m))
+			// Run the migration.
+			err = m.Migrate()
+			// Asserts and tear down.
+			run.asserts(err, source(m), dest(m))
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package migration
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gotsunami/gitlab-copy/gitlab"
+	glab "github.com/xanzy/go-gitlab"
+)
+
+// listBadges returns all badges of project pid, including group badges,
+// fetching all pages.
+func listBadges(c gitlab.GitLaber, pid int) ([]*glab.ProjectBadge, error) {
+	all := make([]*glab.ProjectBadge, 0)
+	opts := &glab.ListProjectBadgesOptions{ListOptions: glab.ListOptions{PerPage: ResultsPerPage, Page: 1}}
+	for {
+		bs, _, err := c.ListProjectBadges(pid, opts)
+		if err != nil {
+			return nil, err
+		}
+		if len(bs) == 0 {
+			break
+		}
+		all = append(all, bs...)
+		opts.Page++
+	}
+	return all, nil
+}
+
+// badgeURL returns the badge URL rawURL with the source host replaced by the
+// target one. Placeholders like %{project_path} are kept as is, rawURL being
+// handled as a plain string since they don't make valid URLs.
+func (m *Migration) badgeURL(rawURL string) string {
+	src, dst := m.Endpoint.SrcClient.BaseURL(), m.Endpoint.DstClient.BaseURL()
+	if src == nil || dst == nil || src.Host == dst.Host {
+		return rawURL
+	}
+	for _, scheme := range []string{"https://", "http://"} {
+		prefix := scheme + src.Host
+		if len(rawURL) < len(prefix) || !strings.EqualFold(rawURL[:len(prefix)], prefix) {
+			continue
+		}
+		rest := rawURL[len(prefix):]
+		// Don't match a longer host name.
+		if rest != "" && !strings.ContainsAny(rest[:1], "/?#") {
+			continue
+		}
+		return dst.Scheme + "://" + dst.Host + rest
+	}
+	return rawURL
+}
+
+// migrateBadges copies the source project badges which don't exist on target
+// yet (by link and image URLs). Group badges are left out, since they are
+// inherited from the group.
+func (m *Migration) migrateBadges() error {
+	source := m.Endpoint.SrcClient
+	target := m.Endpoint.DstClient
+
+	srcProjectID := m.srcProject.ID
+	tarProjectID := m.dstProject.ID
+
+	fmt.Println("Copying badges ...")
+	all, err := listBadges(source, srcProjectID)
+	if err != nil {
+		return fmt.Errorf("source: can't fetch badges: %s", err.Error())
+	}
+	badges := make([]*glab.ProjectBadge, 0)
+	for _, b := range all {
+		if b.Kind != "group" {
+			badges = append(badges, b)
+		}
+	}
+	fmt.Printf("Found %d badges\n", len(badges))
+	tbs, err := listBadges(target, tarProjectID)
+	if err != nil {
+		return fmt.Errorf("target: can't fetch badges: %s", err.Error())
+	}
+	existing := make(map[string]bool)
+	for _, b := range tbs {
+		existing[b.LinkURL+" "+b.ImageURL] = true
+	}
+
+	for _, b := range badges {
+		link, image := m.badgeURL(b.LinkURL), m.badgeURL(b.ImageURL)
+		name := b.Name
+		if name == "" {
+			name = link
+		}
+		if existing[link+" "+image] {
+			fmt.Printf("target: badge %s already exists, skipping...\n", name)
+			continue
+		}
+		bopts := &glab.AddProjectBadgeOptions{
+			LinkURL:  &link,
+			ImageURL: &image,
+		}
+		if b.Name != "" {
+			bopts.Name = &b.Name
+		}
+		if _, _, err := target.AddProjectBadge(tarProjectID, bopts); err != nil {
+			return fmt.Errorf("target: error adding badge '%s': %s", name, err.Error())
+		}
+		fmt.Printf("target: added badge %s\n", name)
+	}
+	return nil
+}
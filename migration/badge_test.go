@@ -0,0 +1,121 @@
+package migration
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gotsunami/gitlab-copy/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	glab "github.com/xanzy/go-gitlab"
+)
+
+func TestMigrateBadges(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	hosts := func(src, dst *fakeClient) {
+		src.baseURL = &url.URL{Scheme: "https", Host: "gitlab.mydomain.com", Path: "/api/v4/"}
+		dst.baseURL = &url.URL{Scheme: "https", Host: "gitlab.myotherdomain.com", Path: "/api/v4/"}
+	}
+
+	runs := []struct {
+		name    string                     // Sub-test name
+		config  string                     // YAML config
+		setup   func(src, dst *fakeClient) // Defines any option before calling Migrate()
+		asserts func(err error, src, dst *fakeClient)
+	}{
+		{
+			"Listing source badges fails",
+			cfg19,
+			func(src, dst *fakeClient) {
+				src.errors.listBadges = errors.New("err")
+			},
+			func(err error, src, dst *fakeClient) {
+				assert.Error(err)
+			},
+		},
+		{
+			"Adding badge fails",
+			cfg19,
+			func(src, dst *fakeClient) {
+				src.badges = []*glab.ProjectBadge{{LinkURL: "https://example.com", ImageURL: "https://example.com/b.svg"}}
+				dst.errors.addBadge = errors.New("err")
+			},
+			func(err error, src, dst *fakeClient) {
+				assert.Error(err)
+			},
+		},
+		{
+			"Group badges and badges existing on target are skipped",
+			cfg19,
+			func(src, dst *fakeClient) {
+				hosts(src, dst)
+				src.badges = []*glab.ProjectBadge{
+					{Name: "group", LinkURL: "https://example.com/g", ImageURL: "https://example.com/g.svg", Kind: "group"},
+					{Name: "docs", LinkURL: "https://example.com/d", ImageURL: "https://example.com/d.svg", Kind: "project"},
+					{Name: "chat", LinkURL: "https://example.com/c", ImageURL: "https://example.com/c.svg", Kind: "project"},
+				}
+				dst.badges = []*glab.ProjectBadge{
+					{Name: "docs", LinkURL: "https://example.com/d", ImageURL: "https://example.com/d.svg", Kind: "project"},
+				}
+			},
+			func(err error, src, dst *fakeClient) {
+				require.NoError(err)
+				if assert.Len(dst.badges, 2) {
+					assert.Equal("chat", dst.badges[1].Name)
+				}
+			},
+		},
+		{
+			"Source host rewritten, placeholders kept",
+			cfg19,
+			func(src, dst *fakeClient) {
+				hosts(src, dst)
+				src.badges = []*glab.ProjectBadge{
+					{
+						Name:     "pipeline",
+						LinkURL:  "https://gitlab.mydomain.com/%{project_path}/-/commits/%{default_branch}",
+						ImageURL: "https://GitLab.MyDomain.com/%{project_path}/badges/%{default_branch}/pipeline.svg",
+						Kind:     "project",
+					},
+					{
+						Name:     "other",
+						LinkURL:  "https://gitlab.mydomain.com.evil.org/%{project_path}",
+						ImageURL: "https://img.shields.io/badge/%{project_id}-blue",
+						Kind:     "project",
+					},
+				}
+			},
+			func(err error, src, dst *fakeClient) {
+				require.NoError(err)
+				require.Len(dst.badges, 2)
+				assert.Equal("https://gitlab.myotherdomain.com/%{project_path}/-/commits/%{default_branch}", dst.badges[0].LinkURL)
+				assert.Equal("https://gitlab.myotherdomain.com/%{project_path}/badges/%{default_branch}/pipeline.svg", dst.badges[0].ImageURL)
+				assert.Equal("https://gitlab.mydomain.com.evil.org/%{project_path}", dst.badges[1].LinkURL)
+				assert.Equal("https://img.shields.io/badge/%{project_id}-blue", dst.badges[1].ImageURL)
+			},
+		},
+	}
+	for _, run := range runs {
+		t.Run(run.name, func(t *testing.T) {
+			conf, err := config.Parse(strings.NewReader(run.config))
+			require.NoError(err)
+			// Load the conf.
+			m, err := New(conf)
+			require.NoError(err)
+			_, err = m.SourceProject(m.params.SrcPrj.Name)
+			require.NoError(err)
+			_, err = m.DestProject(m.params.DstPrj.Name)
+			require.NoError(err)
+			// Setup.
+			run.setup(source(m), dest(m))
+			// Run the migration.
+			err = m.Migrate()
+			// Asserts and tear down.
+			run.asserts(err, source(m), dest(m))
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package migration
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gotsunami/gitlab-copy/gitlab"
+	glab "github.com/xanzy/go-gitlab"
+)
+
+// byPosition sorts board lists by position.
+type byPosition []*glab.BoardList
+
+func (a byPosition) Len() int           { return len(a) }
+func (a byPosition) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+func (a byPosition) Less(i, j int) bool { return a[i].Position < a[j].Position }
+
+// listBoards returns all issue boards of project pid, fetching all pages.
+func listBoards(c gitlab.GitLaber, pid int) ([]*glab.IssueBoard, error) {
+	all := make([]*glab.IssueBoard, 0)
+	opts := &glab.ListIssueBoardsOptions{PerPage: ResultsPerPage, Page: 1}
+	for {
+		bs, _, err := c.ListIssueBoards(pid, opts)
+		if err != nil {
+			return nil, err
+		}
+		if len(bs) == 0 {
+			break
+		}
+		all = append(all, bs...)
+		opts.Page++
+	}
+	return all, nil
+}
+
+// boardListOptions returns the options to create list l on target, resolving
+// its label, milestone or assignee against the target. nil is returned, along
+// with a description of l, if it can't be resolved.
+func (m *Migration) boardListOptions(l *glab.BoardList, labels, milestones map[string]int) (*glab.CreateIssueBoardListOptions, string, error) {
+	switch {
+	case l.Label != nil:
+		id, ok := labels[l.Label.Name]
+		if !ok {
+			return nil, fmt.Sprintf("label '%s'", l.Label.Name), nil
+		}
+		return &glab.CreateIssueBoardListOptions{LabelID: &id}, "", nil
+	case l.Milestone != nil:
+		id, ok := milestones[l.Milestone.Title]
+		if !ok {
+			return nil, fmt.Sprintf("milestone '%s'", l.Milestone.Title), nil
+		}
+		return &glab.CreateIssueBoardListOptions{MilestoneID: &id}, "", nil
+	case l.Assignee != nil:
+		id, err := m.targetUserID(l.Assignee.Username)
+		if err != nil {
+			return nil, "", err
+		}
+		if id == nil {
+			return nil, fmt.Sprintf("assignee @%s", l.Assignee.Username), nil
+		}
+		return &glab.CreateIssueBoardListOptions{AssigneeID: id}, "", nil
+	}
+	return nil, fmt.Sprintf("list %d", l.ID), nil
+}
+
+// migrateBoard recreates the source board b on target, with its lists in the
+// same order. labels and milestones map the target label names and milestone
+// titles to their IDs.
+func (m *Migration) migrateBoard(b *glab.IssueBoard, labels, milestones map[string]int) error {
+	source := m.Endpoint.SrcClient
+	target := m.Endpoint.DstClient
+
+	srcProjectID := m.srcProject.ID
+	tarProjectID := m.dstProject.ID
+
+	lists, _, err := source.GetIssueBoardLists(srcProjectID, b.ID, nil)
+	if err != nil {
+		return fmt.Errorf("source: can't get board '%s' lists: %s", b.Name, err.Error())
+	}
+	sort.Sort(byPosition(lists))
+
+	nb, _, err := target.CreateIssueBoard(tarProjectID, &glab.CreateIssueBoardOptions{Name: &b.Name})
+	if err != nil {
+		return fmt.Errorf("target: error creating board '%s': %s", b.Name, err.Error())
+	}
+	missing := make([]string, 0)
+	for _, l := range lists {
+		lopts, what, err := m.boardListOptions(l, labels, milestones)
+		if err != nil {
+			return err
+		}
+		if lopts == nil {
+			missing = append(missing, what)
+			continue
+		}
+		if _, _, err := target.CreateIssueBoardList(tarProjectID, nb.ID, lopts); err != nil {
+			return fmt.Errorf("target: error creating list in board '%s': %s", b.Name, err.Error())
+		}
+	}
+	fmt.Printf("target: created board %s\n", nb.Name)
+	if len(missing) > 0 {
+		fmt.Printf("target: board %s: no match for %s, lists not created\n", nb.Name, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// migrateBoards copies the source issue boards which don't exist on target
+// yet (by name).
+func (m *Migration) migrateBoards() error {
+	source := m.Endpoint.SrcClient
+	target := m.Endpoint.DstClient
+
+	srcProjectID := m.srcProject.ID
+	tarProjectID := m.dstProject.ID
+
+	fmt.Println("Copying boards ...")
+	boards, err := listBoards(source, srcProjectID)
+	if err != nil {
+		return fmt.Errorf("source: can't fetch boards: %s", err.Error())
+	}
+	fmt.Printf("Found %d boards\n", len(boards))
+	tbs, err := listBoards(target, tarProjectID)
+	if err != nil {
+		return fmt.Errorf("target: can't fetch boards: %s", err.Error())
+	}
+	existing := make(map[string]bool)
+	for _, b := range tbs {
+		existing[b.Name] = true
+	}
+
+	tls, err := listLabels(target, tarProjectID)
+	if err != nil {
+		return fmt.Errorf("target: can't fetch labels: %s", err.Error())
+	}
+	labels := make(map[string]int)
+	for _, l := range tls {
+		labels[l.Name] = l.ID
+	}
+	tms, err := listMilestones(target, tarProjectID)
+	if err != nil {
+		return fmt.Errorf("target: can't fetch milestones: %s", err.Error())
+	}
+	milestones := make(map[string]int)
+	for _, mi := range tms {
+		milestones[mi.Title] = mi.ID
+	}
+
+	for _, b := range boards {
+		if existing[b.Name] {
+			fmt.Printf("target: board %s already exists, skipping...\n", b.Name)
+			continue
+		}
+		if err := m.migrateBoard(b, labels, milestones); err != nil {
+			return err
+		}
+	}
+	return nil
+}
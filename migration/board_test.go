@@ -0,0 +1,171 @@
+package migration
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/gotsunami/gitlab-copy/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	glab "github.com/xanzy/go-gitlab"
+)
+
+func TestMigrateBoards(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	runs := []struct {
+		name    string                     // Sub-test name
+		config  string                     // YAML config
+		setup   func(src, dst *fakeClient) // Defines any option before calling Migrate()
+		asserts func(err error, src, dst *fakeClient)
+	}{
+		{
+			"Listing source boards fails",
+			cfg11,
+			func(src, dst *fakeClient) {
+				src.errors.listBoards = errors.New("err")
+			},
+			func(err error, src, dst *fakeClient) {
+				assert.Error(err)
+			},
+		},
+		{
+			"Getting board lists fails",
+			cfg11,
+			func(src, dst *fakeClient) {
+				src.boards = []*glab.IssueBoard{{ID: 1, Name: "Development"}}
+				src.errors.getBoardLists = errors.New("err")
+			},
+			func(err error, src, dst *fakeClient) {
+				assert.Error(err)
+			},
+		},
+		{
+			"Creating board fails",
+			cfg11,
+			func(src, dst *fakeClient) {
+				src.boards = []*glab.IssueBoard{{ID: 1, Name: "Development"}}
+				dst.errors.createBoard = errors.New("err")
+			},
+			func(err error, src, dst *fakeClient) {
+				assert.Error(err)
+			},
+		},
+		{
+			"Existing boards are skipped",
+			cfg11,
+			func(src, dst *fakeClient) {
+				src.boards = []*glab.IssueBoard{{ID: 1, Name: "Development"}, {ID: 2, Name: "Release"}}
+				dst.boards = []*glab.IssueBoard{{ID: 1, Name: "Development"}}
+			},
+			func(err error, src, dst *fakeClient) {
+				require.NoError(err)
+				if assert.Len(dst.boards, 2) {
+					assert.Equal("Release", dst.boards[1].Name)
+				}
+			},
+		},
+		{
+			"Lists recreated in order, resolved against target",
+			cfg11,
+			func(src, dst *fakeClient) {
+				src.labels = makeLabels("doing", "todo", "review")
+				src.boards = []*glab.IssueBoard{{ID: 1, Name: "Development"}}
+				assignee := &struct {
+					ID       int    `json:"id"`
+					Name     string `json:"name"`
+					Username string `json:"username"`
+				}{ID: 5, Username: "alice"}
+				nobody := &struct {
+					ID       int    `json:"id"`
+					Name     string `json:"name"`
+					Username string `json:"username"`
+				}{ID: 6, Username: "nobody"}
+				src.boardLists = map[int][]*glab.BoardList{
+					1: {
+						{ID: 11, Position: 1, Label: &glab.Label{Name: "doing"}},
+						{ID: 12, Position: 0, Label: &glab.Label{Name: "todo"}},
+						{ID: 13, Position: 2, Milestone: &glab.Milestone{Title: "v1"}},
+						{ID: 14, Position: 3, Assignee: assignee},
+						{ID: 15, Position: 4, Assignee: nobody},
+						{ID: 16, Position: 5, Label: &glab.Label{Name: "gone"}},
+						{ID: 17, Position: 6, Milestone: &glab.Milestone{Title: "v2"}},
+					},
+				}
+				dst.users = makeUsers("bob", "alice")
+				dst.milestones = makeMilestones("v0", "v1")
+			},
+			func(err error, src, dst *fakeClient) {
+				require.NoError(err)
+				require.Len(dst.boards, 1)
+				lists := dst.boardLists[dst.boards[0].ID]
+				if assert.Len(lists, 4) {
+					// Target labels: doing (1), todo (2), review (3).
+					assert.Equal(2, lists[0].Label.ID)
+					assert.Equal(1, lists[1].Label.ID)
+					assert.Equal(1, lists[2].Milestone.ID)
+					assert.Equal(1, lists[3].Assignee.ID)
+				}
+				// Milestones are left as is with labelsOnly.
+				assert.Len(dst.milestones, 2)
+			},
+		},
+		{
+			"Milestone lists resolved once milestones are copied",
+			cfg20,
+			func(src, dst *fakeClient) {
+				src.milestones = makeMilestones("v1")
+				dst.milestones = makeMilestones("v0")
+				src.boards = []*glab.IssueBoard{{ID: 1, Name: "Release"}}
+				src.boardLists = map[int][]*glab.BoardList{
+					1: {{ID: 11, Milestone: &glab.Milestone{Title: "v1"}}},
+				}
+			},
+			func(err error, src, dst *fakeClient) {
+				require.NoError(err)
+				require.Len(dst.boards, 1)
+				require.Len(dst.milestones, 2)
+				lists := dst.boardLists[dst.boards[0].ID]
+				if assert.Len(lists, 1) {
+					assert.Equal(dst.milestones[1].ID, lists[0].Milestone.ID)
+				}
+			},
+		},
+		{
+			"Creating list fails",
+			cfg11,
+			func(src, dst *fakeClient) {
+				src.labels = makeLabels("todo")
+				src.boards = []*glab.IssueBoard{{ID: 1, Name: "Development"}}
+				src.boardLists = map[int][]*glab.BoardList{
+					1: {{ID: 11, Label: &glab.Label{Name: "todo"}}},
+				}
+				dst.errors.createBoardList = errors.New("err")
+			},
+			func(err error, src, dst *fakeClient) {
+				assert.Error(err)
+			},
+		},
+	}
+	for _, run := range runs {
+		t.Run(run.name, func(t *testing.T) {
+			conf, err := config.Parse(strings.NewReader(run.config))
+			require.NoError(err)
+			// Load the conf.
+			m, err := New(conf)
+			require.NoError(err)
+			_, err = m.SourceProject(m.params.SrcPrj.Name)
+			require.NoError(err)
+			_, err = m.DestProject(m.params.DstPrj.Name)
+			require.NoError(err)
+			// Setup.
+			run.setup(source(m), dest(m))
+			// Run the migration.
+			err = m.Migrate()
+			// Asserts and tear down.
+			run.asserts(err, source(m), dest(m))
+		})
+	}
+}
@@ -2,13 +2,21 @@ package migration
 
 import (
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gotsunami/gitlab-copy/gitlab"
 	glab "github.com/xanzy/go-gitlab"
 )
 
+// defaultPerPage is the number of items of a page when not specified.
+const defaultPerPage = 20
+
 type fakeClient struct {
 	baseURL *url.URL
 	errors  struct {
@@ -19,12 +27,117 @@ type fakeClient struct {
 		listUsers                                                    error
 		updateIssue, updateMilestone                                 error
 		baseURL                                                      error
+		editProject                                                  error
+		// Labels
+		listLabelPriorities, updateLabel error
+		// Merge requests
+		createMergeRequest, createMergeRequestNote error
+		getBranch, getMergeRequest                 error
+		listMergeRequests, updateMergeRequest      error
+		// Discussions
+		addDiscussionNote, createDiscussion error
+		listDiscussions, resolveDiscussion  error
+		// Uploads
+		downloadFile, downloadUpload, uploadFile error
+		// Issue links
+		createIssueLink, listIssueRelations error
+		// Award emoji
+		createAwardEmoji, listAwardEmoji error
+		// Resource events
+		listLabelEvents, listMilestoneEvents error
+		listStateEvents, listWeightEvents    error
+		// Time tracking
+		addSpentTime, setTimeEstimate error
+		// Issue health status
+		setIssueHealthStatus error
+		// Wikis
+		createWikiPage, listWikis, uploadWikiAttachment error
+		// Snippets
+		createSnippet, createSnippetNote, listSnippets error
+		// Releases
+		createRelease, getTag, listReleaseMilestones, listReleases error
+		// Members
+		addProjectMember, listProjectMembers error
+		// Epics
+		assignEpicIssue, createEpic, createEpicNote error
+		listEpicIssues, listEpics, setEpicParent    error
+		updateEpic                                  error
+		// Group labels
+		createGroupLabel, listGroupLabels error
+		// Boards
+		createBoard, createBoardList, getBoardLists, listBoards error
+		// CI/CD variables
+		createVariable, listVariables error
+		// Hooks
+		addProjectHook, listProjectHooks error
+		// Users and groups
+		getGroup, getUser error
+		// Protected branches and tags
+		listProtectedBranches, listProtectedTags error
+		listProtectedBranchDeployKeys            error
+		protectBranch, protectTag                error
+		unprotectBranch, unprotectTag            error
+		// Approval rules
+		createApprovalRule, listApprovalRules error
+		// Feature flags
+		createFeatureFlag, listFeatureFlags error
+		// Environments and freeze periods
+		createEnvironment, listEnvironments   error
+		createFreezePeriod, listFreezePeriods error
+		// Badges
+		addBadge, listBadges error
 	}
+	project                  *glab.Project
+	editProjectOptions       *glab.EditProjectOptions
 	labels                   []*glab.Label
+	labelPriorities          map[string]int
 	milestones               []*glab.Milestone
 	users                    []*glab.User
 	issues                   []*glab.Issue
+	deletedIssues            []int
 	issueNotes               []*glab.Note
+	mergeRequests            []*glab.MergeRequest
+	mergeRequestNotes        []*glab.Note
+	discussions              []*glab.Discussion
+	files                    map[string][]byte
+	issueRelations           map[int][]*glab.IssueRelation
+	issueLinks               []*glab.IssueLink
+	awardEmoji               []*glab.AwardEmoji
+	noteAwardEmoji           map[int][]*glab.AwardEmoji
+	labelEvents              []*glab.LabelEvent
+	milestoneEvents          []*glab.MilestoneEvent
+	stateEvents              []*glab.StateEvent
+	weightEvents             []*glab.WeightEvent
+	freeTier                 bool
+	lastNoteID               int
+	timeEstimate             string
+	spentTime                []string
+	healthStatus             string
+	wikis                    []*glab.Wiki
+	snippets                 []*glab.Snippet
+	snippetNotes             []*glab.Note
+	releases                 []*glab.Release
+	releaseMilestones        map[string][]*glab.Milestone
+	tags                     []string
+	members                  []*glab.ProjectMember
+	epics                    []*glab.Epic
+	epicIssues               map[int][]*glab.Issue
+	epicNotes                []*glab.Note
+	groupLabels              []*glab.GroupLabel
+	boards                   []*glab.IssueBoard
+	boardLists               map[int][]*glab.BoardList
+	variables                []*glab.ProjectVariable
+	hooks                    []*glab.ProjectHook
+	groups                   []*glab.Group
+	protectedBranches        []*glab.ProtectedBranch
+	branchDeployKeys         map[int]int
+	protectedTags            []*glab.ProtectedTag
+	approvalRules            []*glab.ProjectApprovalRule
+	featureFlags             []*glab.ProjectFeatureFlag
+	environments             []*glab.Environment
+	freezePeriods            []*glab.FreezePeriod
+	badges                   []*glab.ProjectBadge
+	branches                 []string
 	exitPagination           bool
 	httpErrorRaiseURITooLong bool
 }
@@ -51,8 +164,11 @@ func (c *fakeClient) GetProject(id interface{}, opt *glab.GetProjectOptions, opt
 	if err != nil {
 		return nil, nil, err
 	}
-	p := new(glab.Project)
-	p.Name = "A name"
+	p := c.project
+	if p == nil {
+		p = new(glab.Project)
+		p.Name = "A name"
+	}
 	r := &glab.Response{
 		Response: new(http.Response),
 	}
@@ -60,6 +176,22 @@ func (c *fakeClient) GetProject(id interface{}, opt *glab.GetProjectOptions, opt
 	return p, r, nil
 }
 
+func (c *fakeClient) EditProject(id interface{}, opt *glab.EditProjectOptions, options ...glab.RequestOptionFunc) (*glab.Project, *glab.Response, error) {
+	err := c.errors.editProject
+	if err != nil {
+		return nil, nil, err
+	}
+	c.editProjectOptions = opt
+	p := new(glab.Project)
+	if c.project != nil {
+		*p = *c.project
+	}
+	if opt.Description != nil {
+		p.Description = *opt.Description
+	}
+	return p, nil, nil
+}
+
 func (c *fakeClient) CreateLabel(id interface{}, opt *glab.CreateLabelOptions, options ...glab.RequestOptionFunc) (*glab.Label, *glab.Response, error) {
 	r := &glab.Response{
 		Response: new(http.Response),
@@ -76,10 +208,14 @@ func (c *fakeClient) CreateLabel(id interface{}, opt *glab.CreateLabelOptions, o
 		}
 	}
 	l := &glab.Label{
+		ID:          len(c.labels) + 1,
 		Name:        *opt.Name,
 		Color:       *opt.Color,
 		Description: *opt.Description,
 	}
+	if opt.Priority != nil {
+		c.setLabelPriority(l.Name, *opt.Priority)
+	}
 	c.labels = append(c.labels, l)
 	return l, r, nil
 }
@@ -89,14 +225,66 @@ func (c *fakeClient) ListLabels(id interface{}, opt *glab.ListLabelsOptions, opt
 	if err != nil {
 		return nil, nil, err
 	}
+	if opt != nil && opt.Page > 1 {
+		// No more pages. End of pagination.
+		return nil, nil, nil
+	}
 	return c.labels, nil, nil
 }
 
+func (c *fakeClient) UpdateLabel(id interface{}, opt *glab.UpdateLabelOptions, options ...glab.RequestOptionFunc) (*glab.Label, *glab.Response, error) {
+	err := c.errors.updateLabel
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, l := range c.labels {
+		if l.Name == *opt.Name {
+			if opt.Priority != nil {
+				c.setLabelPriority(l.Name, *opt.Priority)
+			}
+			return l, nil, nil
+		}
+	}
+	// Group labels can be prioritized in the project too.
+	for _, l := range c.groupLabels {
+		if l.Name == *opt.Name {
+			if opt.Priority != nil {
+				c.setLabelPriority(l.Name, *opt.Priority)
+			}
+			return (*glab.Label)(l), nil, nil
+		}
+	}
+	return nil, nil, fmt.Errorf("label %q not found", *opt.Name)
+}
+
+func (c *fakeClient) ListLabelPriorities(id interface{}, opt *glab.ListLabelsOptions, options ...glab.RequestOptionFunc) (map[string]int, *glab.Response, error) {
+	err := c.errors.listLabelPriorities
+	if err != nil {
+		return nil, nil, err
+	}
+	return c.labelPriorities, nil, nil
+}
+
+func (c *fakeClient) setLabelPriority(name string, priority int) {
+	if c.labelPriorities == nil {
+		c.labelPriorities = make(map[string]int)
+	}
+	c.labelPriorities[name] = priority
+}
+
 func (c *fakeClient) ListMilestones(id interface{}, opt *glab.ListMilestonesOptions, options ...glab.RequestOptionFunc) ([]*glab.Milestone, *glab.Response, error) {
 	err := c.errors.listMilestones
 	if err != nil {
 		return nil, nil, err
 	}
+	if opt != nil && opt.Page > 1 {
+		// No more pages. End of pagination.
+		return nil, nil, nil
+	}
+	// Only the first page, of GitLab's default size.
+	if opt == nil && len(c.milestones) > defaultPerPage {
+		return c.milestones[:defaultPerPage], nil, nil
+	}
 	return c.milestones, nil, nil
 }
 
@@ -106,8 +294,14 @@ func (c *fakeClient) CreateMilestone(id interface{}, opt *glab.CreateMilestoneOp
 		return nil, nil, err
 	}
 	m := &glab.Milestone{
-		ID:    len(c.milestones),
-		Title: *opt.Title,
+		ID:        len(c.milestones),
+		Title:     *opt.Title,
+		StartDate: opt.StartDate,
+		DueDate:   opt.DueDate,
+		State:     "active",
+	}
+	if opt.Description != nil {
+		m.Description = *opt.Description
 	}
 	for _, p := range c.milestones {
 		if p.Title == m.Title {
@@ -128,9 +322,25 @@ func (c *fakeClient) UpdateMilestone(
 	if err != nil {
 		return nil, nil, err
 	}
-	m := c.milestones[id.(int)]
-	m.State = *opt.StateEvent
-	return m, nil, nil
+	for _, m := range c.milestones {
+		if m.ID != milestone {
+			continue
+		}
+		if opt.Description != nil {
+			m.Description = *opt.Description
+		}
+		if opt.StartDate != nil {
+			m.StartDate = opt.StartDate
+		}
+		if opt.DueDate != nil {
+			m.DueDate = opt.DueDate
+		}
+		if opt.StateEvent != nil {
+			m.State = map[string]string{"close": "closed", "activate": "active"}[*opt.StateEvent]
+		}
+		return m, nil, nil
+	}
+	return nil, nil, fmt.Errorf("milestone %d not found", milestone)
 }
 
 func (c *fakeClient) ListProjectIssues(
@@ -149,11 +359,12 @@ func (c *fakeClient) ListProjectIssues(
 	return c.issues, nil, nil
 }
 
-func (c *fakeClient) DeleteIssue(interface{}, int, ...glab.RequestOptionFunc) (*glab.Response, error) {
+func (c *fakeClient) DeleteIssue(pid interface{}, issue int, options ...glab.RequestOptionFunc) (*glab.Response, error) {
 	err := c.errors.deleteIssue
 	if err != nil {
 		return nil, err
 	}
+	c.deletedIssues = append(c.deletedIssues, issue)
 	return nil, nil
 }
 
@@ -182,8 +393,28 @@ func (c *fakeClient) CreateIssue(pid interface{}, opt *glab.CreateIssueOptions,
 		Title:    *opt.Title,
 		Assignee: &glab.IssueAssignee{},
 	}
-	if opt.AssigneeIDs != nil && len(*opt.AssigneeIDs) > 0 {
-		i.Assignee.Username = "mat"
+	if opt.Description != nil {
+		i.Description = *opt.Description
+	}
+	if opt.Confidential != nil {
+		i.Confidential = *opt.Confidential
+	}
+	if opt.Weight != nil {
+		i.Weight = *opt.Weight
+	}
+	i.DueDate = opt.DueDate
+	i.IssueType = opt.IssueType
+	if opt.AssigneeIDs != nil {
+		for _, id := range *opt.AssigneeIDs {
+			for _, u := range c.users {
+				if u.ID == id {
+					i.Assignees = append(i.Assignees, &glab.IssueAssignee{ID: u.ID, Username: u.Username})
+				}
+			}
+		}
+		if len(i.Assignees) > 0 {
+			i.Assignee.Username = i.Assignees[0].Username
+		}
 	}
 	for _, p := range c.issues {
 		if p.Title == i.Title {
@@ -199,9 +430,54 @@ func (c *fakeClient) ListUsers(opt *glab.ListUsersOptions, opts ...glab.RequestO
 	if err != nil {
 		return nil, nil, err
 	}
+	if opt != nil && opt.Username != nil {
+		users := make([]*glab.User, 0)
+		for _, u := range c.users {
+			if u.Username == *opt.Username {
+				users = append(users, u)
+			}
+		}
+		return users, nil, nil
+	}
+	// Only the first page, of GitLab's default size.
+	if len(c.users) > defaultPerPage {
+		return c.users[:defaultPerPage], nil, nil
+	}
 	return c.users, nil, nil
 }
 
+func (c *fakeClient) GetUser(user int, opt glab.GetUsersOptions, options ...glab.RequestOptionFunc) (*glab.User, *glab.Response, error) {
+	err := c.errors.getUser
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, u := range c.users {
+		if u.ID == user {
+			return u, nil, nil
+		}
+	}
+	return nil, nil, fmt.Errorf("user %d not found", user)
+}
+
+func (c *fakeClient) GetGroup(gid interface{}, opt *glab.GetGroupOptions, options ...glab.RequestOptionFunc) (*glab.Group, *glab.Response, error) {
+	r := &glab.Response{
+		Response: new(http.Response),
+	}
+	err := c.errors.getGroup
+	if err != nil {
+		r.StatusCode = http.StatusInternalServerError
+		return nil, r, err
+	}
+	for _, g := range c.groups {
+		if g.ID == gid || g.FullPath == gid {
+			r.StatusCode = http.StatusOK
+			return g, r, nil
+		}
+	}
+	r.StatusCode = http.StatusNotFound
+	return nil, r, fmt.Errorf("group %v not found", gid)
+}
+
 func (c *fakeClient) ListIssueNotes(
 	pid interface{},
 	issue int,
@@ -234,17 +510,52 @@ func (c *fakeClient) CreateIssueNote(
 		}
 		return nil, r, err
 	}
-	return nil, nil, nil
+	n := &glab.Note{ID: c.nextNoteID(), Body: *opt.Body}
+	c.issueNotes = append(c.issueNotes, n)
+	return n, nil, nil
 }
 
-func (c *fakeClient) UpdateIssue(interface{}, int, *glab.UpdateIssueOptions, ...glab.RequestOptionFunc) (*glab.Issue, *glab.Response, error) {
+func (c *fakeClient) UpdateIssue(pid interface{}, issue int, opt *glab.UpdateIssueOptions, options ...glab.RequestOptionFunc) (*glab.Issue, *glab.Response, error) {
 	err := c.errors.updateIssue
 	if err != nil {
 		return nil, nil, err
 	}
+	for _, i := range c.issues {
+		if i.IID == issue {
+			if opt.StateEvent != nil {
+				i.State = *opt.StateEvent
+			}
+			if opt.DiscussionLocked != nil {
+				i.DiscussionLocked = *opt.DiscussionLocked
+			}
+			return i, nil, nil
+		}
+	}
 	return nil, nil, nil
 }
 
+func (c *fakeClient) GetIssueWithHealthStatus(pid interface{}, id int, options ...glab.RequestOptionFunc) (*glab.Issue, string, *glab.Response, error) {
+	err := c.errors.getIssue
+	if err != nil {
+		return nil, "", nil, err
+	}
+	return c.issues[id], c.healthStatus, nil, nil
+}
+
+func (c *fakeClient) SetIssueHealthStatus(pid interface{}, issue int, status string, options ...glab.RequestOptionFunc) (*glab.Response, error) {
+	err := c.errors.setIssueHealthStatus
+	if err != nil {
+		return nil, err
+	}
+	c.healthStatus = status
+	return nil, nil
+}
+
+func (c *fakeClient) nextNoteID() int {
+	c.lastNoteID++
+	return c.lastNoteID
+}
+
 func (c *fakeClient) clearMilestones() {
 	c.milestones = nil
 	c.milestones = make([]*glab.Milestone, 0)
@@ -259,3 +570,1337 @@ func (c *fakeClient) clearIssues() {
 	c.issues = nil
 	c.issues = make([]*glab.Issue, 0)
 }
+
+func (c *fakeClient) ListProjectMergeRequests(
+	pid interface{},
+	opt *glab.ListProjectMergeRequestsOptions,
+	options ...glab.RequestOptionFunc,
+) ([]*glab.MergeRequest, *glab.Response, error) {
+	err := c.errors.listMergeRequests
+	if err != nil {
+		return nil, nil, err
+	}
+	if opt != nil && opt.ListOptions.Page > 1 {
+		// No more pages. End of pagination.
+		return nil, nil, nil
+	}
+	return c.mergeRequests, nil, nil
+}
+
+func (c *fakeClient) GetMergeRequest(
+	pid interface{},
+	id int,
+	opt *glab.GetMergeRequestsOptions,
+	options ...glab.RequestOptionFunc,
+) (*glab.MergeRequest, *glab.Response, error) {
+	err := c.errors.getMergeRequest
+	if err != nil {
+		return nil, nil, err
+	}
+	return c.mergeRequests[id], nil, nil
+}
+
+func (c *fakeClient) CreateMergeRequest(
+	pid interface{},
+	opt *glab.CreateMergeRequestOptions,
+	options ...glab.RequestOptionFunc,
+) (*glab.MergeRequest, *glab.Response, error) {
+	err := c.errors.createMergeRequest
+	if err != nil {
+		return nil, nil, err
+	}
+	mr := &glab.MergeRequest{
+		ID:           len(c.mergeRequests),
+		IID:          len(c.mergeRequests) + 1,
+		Title:        *opt.Title,
+		SourceBranch: *opt.SourceBranch,
+		TargetBranch: *opt.TargetBranch,
+		State:        "opened",
+	}
+	c.mergeRequests = append(c.mergeRequests, mr)
+	return mr, nil, nil
+}
+
+func (c *fakeClient) UpdateMergeRequest(
+	pid interface{},
+	id int,
+	opt *glab.UpdateMergeRequestOptions,
+	options ...glab.RequestOptionFunc,
+) (*glab.MergeRequest, *glab.Response, error) {
+	err := c.errors.updateMergeRequest
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, mr := range c.mergeRequests {
+		if mr.IID == id && opt.StateEvent != nil {
+			mr.State = *opt.StateEvent
+			return mr, nil, nil
+		}
+	}
+	return nil, nil, nil
+}
+
+func (c *fakeClient) CreateMergeRequestNote(
+	pid interface{},
+	mergeRequest int,
+	opt *glab.CreateMergeRequestNoteOptions,
+	options ...glab.RequestOptionFunc,
+) (*glab.Note, *glab.Response, error) {
+	err := c.errors.createMergeRequestNote
+	if err != nil {
+		return nil, nil, err
+	}
+	n := &glab.Note{Body: *opt.Body}
+	c.mergeRequestNotes = append(c.mergeRequestNotes, n)
+	return n, nil, nil
+}
+
+func (c *fakeClient) GetBranch(pid interface{}, branch string, options ...glab.RequestOptionFunc) (*glab.Branch, *glab.Response, error) {
+	r := &glab.Response{
+		Response: new(http.Response),
+	}
+	err := c.errors.getBranch
+	if err != nil {
+		r.StatusCode = http.StatusInternalServerError
+		return nil, r, err
+	}
+	for _, b := range c.branches {
+		if b == branch {
+			r.StatusCode = http.StatusOK
+			return &glab.Branch{Name: b}, r, nil
+		}
+	}
+	r.StatusCode = http.StatusNotFound
+	return nil, r, fmt.Errorf("branch %q not found", branch)
+}
+
+func (c *fakeClient) listDiscussions(opt *glab.ListOptions) ([]*glab.Discussion, *glab.Response, error) {
+	err := c.errors.listDiscussions
+	if err != nil {
+		return nil, nil, err
+	}
+	if opt != nil && opt.Page > 1 {
+		// No more pages. End of pagination.
+		return nil, nil, nil
+	}
+	return c.discussions, nil, nil
+}
+
+func (c *fakeClient) createDiscussion(body string) (*glab.Discussion, *glab.Response, error) {
+	r := &glab.Response{
+		Response: new(http.Response),
+	}
+	err := c.errors.createDiscussion
+	if err != nil {
+		if c.httpErrorRaiseURITooLong {
+			r.Response.StatusCode = http.StatusRequestURITooLong
+		}
+		return nil, r, err
+	}
+	d := &glab.Discussion{
+		ID:    fmt.Sprintf("d%d", len(c.discussions)),
+		Notes: []*glab.Note{{ID: c.nextNoteID(), Body: body}},
+	}
+	c.discussions = append(c.discussions, d)
+	return d, r, nil
+}
+
+func (c *fakeClient) addDiscussionNote(discussion, body string) (*glab.Note, *glab.Response, error) {
+	err := c.errors.addDiscussionNote
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, d := range c.discussions {
+		if d.ID == discussion {
+			n := &glab.Note{ID: c.nextNoteID(), Body: body}
+			d.Notes = append(d.Notes, n)
+			return n, nil, nil
+		}
+	}
+	return nil, nil, fmt.Errorf("discussion %q not found", discussion)
+}
+
+func (c *fakeClient) ListIssueDiscussions(
+	pid interface{},
+	issue int,
+	opt *glab.ListIssueDiscussionsOptions,
+	options ...glab.RequestOptionFunc,
+) ([]*glab.Discussion, *glab.Response, error) {
+	return c.listDiscussions((*glab.ListOptions)(opt))
+}
+
+func (c *fakeClient) CreateIssueDiscussion(
+	pid interface{},
+	issue int,
+	opt *glab.CreateIssueDiscussionOptions,
+	options ...glab.RequestOptionFunc,
+) (*glab.Discussion, *glab.Response, error) {
+	return c.createDiscussion(*opt.Body)
+}
+
+func (c *fakeClient) AddIssueDiscussionNote(
+	pid interface{},
+	issue int,
+	discussion string,
+	opt *glab.AddIssueDiscussionNoteOptions,
+	options ...glab.RequestOptionFunc,
+) (*glab.Note, *glab.Response, error) {
+	return c.addDiscussionNote(discussion, *opt.Body)
+}
+
+func (c *fakeClient) ListMergeRequestDiscussions(
+	pid interface{},
+	mergeRequest int,
+	opt *glab.ListMergeRequestDiscussionsOptions,
+	options ...glab.RequestOptionFunc,
+) ([]*glab.Discussion, *glab.Response, error) {
+	return c.listDiscussions((*glab.ListOptions)(opt))
+}
+
+func (c *fakeClient) CreateMergeRequestDiscussion(
+	pid interface{},
+	mergeRequest int,
+	opt *glab.CreateMergeRequestDiscussionOptions,
+	options ...glab.RequestOptionFunc,
+) (*glab.Discussion, *glab.Response, error) {
+	return c.createDiscussion(*opt.Body)
+}
+
+func (c *fakeClient) AddMergeRequestDiscussionNote(
+	pid interface{},
+	mergeRequest int,
+	discussion string,
+	opt *glab.AddMergeRequestDiscussionNoteOptions,
+	options ...glab.RequestOptionFunc,
+) (*glab.Note, *glab.Response, error) {
+	return c.addDiscussionNote(discussion, *opt.Body)
+}
+
+func (c *fakeClient) ResolveMergeRequestDiscussion(
+	pid interface{},
+	mergeRequest int,
+	discussion string,
+	opt *glab.ResolveMergeRequestDiscussionOptions,
+	options ...glab.RequestOptionFunc,
+) (*glab.Discussion, *glab.Response, error) {
+	err := c.errors.resolveDiscussion
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, d := range c.discussions {
+		if d.ID == discussion {
+			for _, n := range d.Notes {
+				n.Resolvable = true
+				n.Resolved = *opt.Resolved
+			}
+			return d, nil, nil
+		}
+	}
+	return nil, nil, fmt.Errorf("discussion %q not found", discussion)
+}
+
+func (c *fakeClient) UploadFile(
+	pid interface{},
+	content io.Reader,
+	filename string,
+	options ...glab.RequestOptionFunc,
+) (*glab.ProjectFile, *glab.Response, error) {
+	err := c.errors.uploadFile
+	if err != nil {
+		return nil, nil, err
+	}
+	data, err := io.ReadAll(content)
+	if err != nil {
+		return nil, nil, err
+	}
+	u := fmt.Sprintf("/uploads/%032d/%s", len(c.files), filename)
+	if c.files == nil {
+		c.files = make(map[string][]byte)
+	}
+	c.files[u] = data
+	return &glab.ProjectFile{Alt: filename, URL: u}, nil, nil
+}
+
+func (c *fakeClient) DownloadFile(rawURL string, options ...glab.RequestOptionFunc) ([]byte, *glab.Response, error) {
+	err := c.errors.downloadFile
+	if err != nil {
+		return nil, nil, err
+	}
+	data, ok := c.files[rawURL]
+	if !ok {
+		return nil, nil, fmt.Errorf("file %q not found", rawURL)
+	}
+	return data, nil, nil
+}
+
+func (c *fakeClient) DownloadUpload(pid interface{}, secret, filename string, options ...glab.RequestOptionFunc) ([]byte, *glab.Response, error) {
+	err := c.errors.downloadUpload
+	if err != nil {
+		return nil, nil, err
+	}
+	u := fmt.Sprintf("/uploads/%s/%s", secret, url.PathEscape(filename))
+	data, ok := c.files[u]
+	if !ok {
+		return nil, nil, fmt.Errorf("upload %q not found", u)
+	}
+	return data, nil, nil
+}
+
+func (c *fakeClient) ListIssueRelations(pid interface{}, issue int, options ...glab.RequestOptionFunc) ([]*glab.IssueRelation, *glab.Response, error) {
+	err := c.errors.listIssueRelations
+	if err != nil {
+		return nil, nil, err
+	}
+	return c.issueRelations[issue], nil, nil
+}
+
+func (c *fakeClient) CreateIssueLink(
+	pid interface{},
+	issue int,
+	opt *glab.CreateIssueLinkOptions,
+	options ...glab.RequestOptionFunc,
+) (*glab.IssueLink, *glab.Response, error) {
+	err := c.errors.createIssueLink
+	if err != nil {
+		return nil, nil, err
+	}
+	tiid, err := strconv.Atoi(*opt.TargetIssueIID)
+	if err != nil {
+		return nil, nil, err
+	}
+	l := &glab.IssueLink{
+		SourceIssue: &glab.Issue{IID: issue},
+		TargetIssue: &glab.Issue{IID: tiid},
+		LinkType:    *opt.LinkType,
+	}
+	c.issueLinks = append(c.issueLinks, l)
+	return l, nil, nil
+}
+
+func (c *fakeClient) ListIssueAwardEmoji(
+	pid interface{},
+	issue int,
+	opt *glab.ListAwardEmojiOptions,
+	options ...glab.RequestOptionFunc,
+) ([]*glab.AwardEmoji, *glab.Response, error) {
+	err := c.errors.listAwardEmoji
+	if err != nil {
+		return nil, nil, err
+	}
+	if opt != nil && opt.Page > 1 {
+		// No more pages. End of pagination.
+		return nil, nil, nil
+	}
+	return c.awardEmoji, nil, nil
+}
+
+func (c *fakeClient) CreateIssueAwardEmoji(
+	pid interface{},
+	issue int,
+	opt *glab.CreateAwardEmojiOptions,
+	options ...glab.RequestOptionFunc,
+) (*glab.AwardEmoji, *glab.Response, error) {
+	err := c.errors.createAwardEmoji
+	if err != nil {
+		return nil, nil, err
+	}
+	a := &glab.AwardEmoji{Name: opt.Name}
+	c.awardEmoji = append(c.awardEmoji, a)
+	return a, nil, nil
+}
+
+func (c *fakeClient) ListIssuesAwardEmojiOnNote(
+	pid interface{},
+	issue, note int,
+	opt *glab.ListAwardEmojiOptions,
+	options ...glab.RequestOptionFunc,
+) ([]*glab.AwardEmoji, *glab.Response, error) {
+	err := c.errors.listAwardEmoji
+	if err != nil {
+		return nil, nil, err
+	}
+	if opt != nil && opt.Page > 1 {
+		// No more pages. End of pagination.
+		return nil, nil, nil
+	}
+	return c.noteAwardEmoji[note], nil, nil
+}
+
+func (c *fakeClient) CreateIssuesAwardEmojiOnNote(
+	pid interface{},
+	issue, note int,
+	opt *glab.CreateAwardEmojiOptions,
+	options ...glab.RequestOptionFunc,
+) (*glab.AwardEmoji, *glab.Response, error) {
+	err := c.errors.createAwardEmoji
+	if err != nil {
+		return nil, nil, err
+	}
+	if c.noteAwardEmoji == nil {
+		c.noteAwardEmoji = make(map[int][]*glab.AwardEmoji)
+	}
+	a := &glab.AwardEmoji{Name: opt.Name}
+	c.noteAwardEmoji[note] = append(c.noteAwardEmoji[note], a)
+	return a, nil, nil
+}
+
+func (c *fakeClient) SetTimeEstimate(
+	pid interface{},
+	issue int,
+	opt *glab.SetTimeEstimateOptions,
+	options ...glab.RequestOptionFunc,
+) (*glab.TimeStats, *glab.Response, error) {
+	err := c.errors.setTimeEstimate
+	if err != nil {
+		return nil, nil, err
+	}
+	c.timeEstimate = *opt.Duration
+	return &glab.TimeStats{HumanTimeEstimate: *opt.Duration}, nil, nil
+}
+
+func (c *fakeClient) AddSpentTime(
+	pid interface{},
+	issue int,
+	opt *glab.AddSpentTimeOptions,
+	options ...glab.RequestOptionFunc,
+) (*glab.TimeStats, *glab.Response, error) {
+	err := c.errors.addSpentTime
+	if err != nil {
+		return nil, nil, err
+	}
+	c.spentTime = append(c.spentTime, *opt.Duration)
+	return &glab.TimeStats{}, nil, nil
+}
+
+func (c *fakeClient) ListWikis(pid interface{}, opt *glab.ListWikisOptions, options ...glab.RequestOptionFunc) ([]*glab.Wiki, *glab.Response, error) {
+	err := c.errors.listWikis
+	if err != nil {
+		return nil, nil, err
+	}
+	return c.wikis, nil, nil
+}
+
+func (c *fakeClient) CreateWikiPage(pid interface{}, opt *glab.CreateWikiPageOptions, options ...glab.RequestOptionFunc) (*glab.Wiki, *glab.Response, error) {
+	err := c.errors.createWikiPage
+	if err != nil {
+		return nil, nil, err
+	}
+	w := &glab.Wiki{
+		Title:   path.Base(*opt.Title),
+		Slug:    strings.ReplaceAll(*opt.Title, " ", "-"),
+		Content: *opt.Content,
+		Format:  *opt.Format,
+	}
+	c.wikis = append(c.wikis, w)
+	return w, nil, nil
+}
+
+func (c *fakeClient) UploadWikiAttachment(
+	pid interface{},
+	content io.Reader,
+	filename string,
+	options ...glab.RequestOptionFunc,
+) (*gitlab.WikiAttachment, *glab.Response, error) {
+	err := c.errors.uploadWikiAttachment
+	if err != nil {
+		return nil, nil, err
+	}
+	data, err := io.ReadAll(content)
+	if err != nil {
+		return nil, nil, err
+	}
+	u := fmt.Sprintf("uploads/%032d/%s", len(c.files), filename)
+	if c.files == nil {
+		c.files = make(map[string][]byte)
+	}
+	c.files[u] = data
+	a := &gitlab.WikiAttachment{FileName: filename, FilePath: u}
+	a.Link.URL = u
+	return a, nil, nil
+}
+
+func (c *fakeClient) ListSnippets(pid interface{}, opt *glab.ListProjectSnippetsOptions, options ...glab.RequestOptionFunc) ([]*glab.Snippet, *glab.Response, error) {
+	err := c.errors.listSnippets
+	if err != nil {
+		return nil, nil, err
+	}
+	if opt != nil && opt.Page > 1 {
+		// No more pages. End of pagination.
+		return nil, nil, nil
+	}
+	return c.snippets, nil, nil
+}
+
+func (c *fakeClient) CreateSnippet(pid interface{}, opt *glab.CreateProjectSnippetOptions, options ...glab.RequestOptionFunc) (*glab.Snippet, *glab.Response, error) {
+	err := c.errors.createSnippet
+	if err != nil {
+		return nil, nil, err
+	}
+	s := &glab.Snippet{
+		ID:          len(c.snippets),
+		Title:       *opt.Title,
+		Description: *opt.Description,
+		Visibility:  string(*opt.Visibility),
+	}
+	for _, f := range *opt.Files {
+		u := fmt.Sprintf("/snippets/%d/raw/main/%s", s.ID, *f.FilePath)
+		s.Files = append(s.Files, struct {
+			Path   string `json:"path"`
+			RawURL string `json:"raw_url"`
+		}{*f.FilePath, u})
+		if c.files == nil {
+			c.files = make(map[string][]byte)
+		}
+		c.files[u] = []byte(*f.Content)
+	}
+	c.snippets = append(c.snippets, s)
+	return s, nil, nil
+}
+
+func (c *fakeClient) CreateSnippetNote(pid interface{}, snippet int, opt *glab.CreateSnippetNoteOptions, options ...glab.RequestOptionFunc) (*glab.Note, *glab.Response, error) {
+	err := c.errors.createSnippetNote
+	if err != nil {
+		return nil, nil, err
+	}
+	n := &glab.Note{ID: c.nextNoteID(), Body: *opt.Body}
+	c.snippetNotes = append(c.snippetNotes, n)
+	return n, nil, nil
+}
+
+func (c *fakeClient) ListSnippetDiscussions(
+	pid interface{},
+	snippet int,
+	opt *glab.ListSnippetDiscussionsOptions,
+	options ...glab.RequestOptionFunc,
+) ([]*glab.Discussion, *glab.Response, error) {
+	return c.listDiscussions((*glab.ListOptions)(opt))
+}
+
+func (c *fakeClient) CreateSnippetDiscussion(
+	pid interface{},
+	snippet int,
+	opt *glab.CreateSnippetDiscussionOptions,
+	options ...glab.RequestOptionFunc,
+) (*glab.Discussion, *glab.Response, error) {
+	return c.createDiscussion(*opt.Body)
+}
+
+func (c *fakeClient) AddSnippetDiscussionNote(
+	pid interface{},
+	snippet int,
+	discussion string,
+	opt *glab.AddSnippetDiscussionNoteOptions,
+	options ...glab.RequestOptionFunc,
+) (*glab.Note, *glab.Response, error) {
+	return c.addDiscussionNote(discussion, *opt.Body)
+}
+
+func (c *fakeClient) ListReleases(pid interface{}, opt *glab.ListReleasesOptions, options ...glab.RequestOptionFunc) ([]*glab.Release, *glab.Response, error) {
+	err := c.errors.listReleases
+	if err != nil {
+		return nil, nil, err
+	}
+	if opt != nil && opt.Page > 1 {
+		// No more pages. End of pagination.
+		return nil, nil, nil
+	}
+	return c.releases, nil, nil
+}
+
+func (c *fakeClient) CreateRelease(pid interface{}, opt *glab.CreateReleaseOptions, options ...glab.RequestOptionFunc) (*glab.Release, *glab.Response, error) {
+	err := c.errors.createRelease
+	if err != nil {
+		return nil, nil, err
+	}
+	r := &glab.Release{
+		Name:        *opt.Name,
+		TagName:     *opt.TagName,
+		Description: *opt.Description,
+		ReleasedAt:  opt.ReleasedAt,
+	}
+	if opt.Assets != nil {
+		for _, l := range opt.Assets.Links {
+			r.Assets.Links = append(r.Assets.Links, &glab.ReleaseLink{Name: *l.Name, URL: *l.URL, LinkType: *l.LinkType})
+		}
+	}
+	if opt.Milestones != nil {
+		if c.releaseMilestones == nil {
+			c.releaseMilestones = make(map[string][]*glab.Milestone)
+		}
+		for _, t := range *opt.Milestones {
+			c.releaseMilestones[r.TagName] = append(c.releaseMilestones[r.TagName], &glab.Milestone{Title: t})
+		}
+	}
+	c.releases = append(c.releases, r)
+	return r, nil, nil
+}
+
+func (c *fakeClient) ListReleaseMilestones(pid interface{}, tag string, options ...glab.RequestOptionFunc) ([]*glab.Milestone, *glab.Response, error) {
+	err := c.errors.listReleaseMilestones
+	if err != nil {
+		return nil, nil, err
+	}
+	return c.releaseMilestones[tag], nil, nil
+}
+
+func (c *fakeClient) GetTag(pid interface{}, tag string, options ...glab.RequestOptionFunc) (*glab.Tag, *glab.Response, error) {
+	r := &glab.Response{
+		Response: new(http.Response),
+	}
+	err := c.errors.getTag
+	if err != nil {
+		r.StatusCode = http.StatusInternalServerError
+		return nil, r, err
+	}
+	for _, t := range c.tags {
+		if t == tag {
+			r.StatusCode = http.StatusOK
+			return &glab.Tag{Name: t}, r, nil
+		}
+	}
+	r.StatusCode = http.StatusNotFound
+	return nil, r, fmt.Errorf("tag %q not found", tag)
+}
+
+func (c *fakeClient) ListProjectMembers(pid interface{}, opt *glab.ListProjectMembersOptions, options ...glab.RequestOptionFunc) ([]*glab.ProjectMember, *glab.Response, error) {
+	err := c.errors.listProjectMembers
+	if err != nil {
+		return nil, nil, err
+	}
+	if opt != nil && opt.Page > 1 {
+		// No more pages. End of pagination.
+		return nil, nil, nil
+	}
+	return c.members, nil, nil
+}
+
+func (c *fakeClient) AddProjectMember(pid interface{}, opt *glab.AddProjectMemberOptions, options ...glab.RequestOptionFunc) (*glab.ProjectMember, *glab.Response, error) {
+	r := &glab.Response{
+		Response: new(http.Response),
+	}
+	err := c.errors.addProjectMember
+	if err != nil {
+		r.StatusCode = http.StatusInternalServerError
+		return nil, r, err
+	}
+	id := opt.UserID.(int)
+	for _, mb := range c.members {
+		if mb.ID == id {
+			r.StatusCode = http.StatusConflict
+			return nil, r, fmt.Errorf("member %d already exists", id)
+		}
+	}
+	mb := &glab.ProjectMember{ID: id, AccessLevel: *opt.AccessLevel}
+	for _, u := range c.users {
+		if u.ID == id {
+			mb.Username = u.Username
+		}
+	}
+	if opt.ExpiresAt != nil {
+		t, err := time.Parse("2006-01-02", *opt.ExpiresAt)
+		if err != nil {
+			return nil, nil, err
+		}
+		expires := glab.ISOTime(t)
+		mb.ExpiresAt = &expires
+	}
+	c.members = append(c.members, mb)
+	r.StatusCode = http.StatusCreated
+	return mb, r, nil
+}
+
+func (c *fakeClient) ListGroupEpics(gid interface{}, opt *glab.ListGroupEpicsOptions, options ...glab.RequestOptionFunc) ([]*glab.Epic, *glab.Response, error) {
+	err := c.errors.listEpics
+	if err != nil {
+		return nil, nil, err
+	}
+	if opt != nil && opt.Page > 1 {
+		// No more pages. End of pagination.
+		return nil, nil, nil
+	}
+	return c.epics, nil, nil
+}
+
+func (c *fakeClient) CreateEpic(gid interface{}, opt *glab.CreateEpicOptions, options ...glab.RequestOptionFunc) (*glab.Epic, *glab.Response, error) {
+	err := c.errors.createEpic
+	if err != nil {
+		return nil, nil, err
+	}
+	e := &glab.Epic{
+		ID:          100 + len(c.epics),
+		IID:         len(c.epics) + 1,
+		Title:       *opt.Title,
+		Description: *opt.Description,
+		State:       "opened",
+	}
+	if opt.Labels != nil {
+		e.Labels = *opt.Labels
+	}
+	if opt.StartDateIsFixed != nil {
+		e.StartDateIsFixed = *opt.StartDateIsFixed
+		e.StartDateFixed = opt.StartDateFixed
+	}
+	if opt.DueDateIsFixed != nil {
+		e.DueDateIsFixed = *opt.DueDateIsFixed
+		e.DueDateFixed = opt.DueDateFixed
+	}
+	c.epics = append(c.epics, e)
+	return e, nil, nil
+}
+
+func (c *fakeClient) epic(iid int) *glab.Epic {
+	for _, e := range c.epics {
+		if e.IID == iid {
+			return e
+		}
+	}
+	return nil
+}
+
+func (c *fakeClient) UpdateEpic(gid interface{}, epic int, opt *glab.UpdateEpicOptions, options ...glab.RequestOptionFunc) (*glab.Epic, *glab.Response, error) {
+	err := c.errors.updateEpic
+	if err != nil {
+		return nil, nil, err
+	}
+	e := c.epic(epic)
+	if e == nil {
+		return nil, nil, fmt.Errorf("epic %d not found", epic)
+	}
+	if opt.StateEvent != nil && *opt.StateEvent == "close" {
+		e.State = "closed"
+	}
+	return e, nil, nil
+}
+
+func (c *fakeClient) SetEpicParent(gid interface{}, epic int, parentID int, options ...glab.RequestOptionFunc) (*glab.Response, error) {
+	err := c.errors.setEpicParent
+	if err != nil {
+		return nil, err
+	}
+	e := c.epic(epic)
+	if e == nil {
+		return nil, fmt.Errorf("epic %d not found", epic)
+	}
+	e.ParentID = parentID
+	return nil, nil
+}
+
+func (c *fakeClient) ListEpicIssues(gid interface{}, epic int, opt *glab.ListOptions, options ...glab.RequestOptionFunc) ([]*glab.Issue, *glab.Response, error) {
+	err := c.errors.listEpicIssues
+	if err != nil {
+		return nil, nil, err
+	}
+	if opt != nil && opt.Page > 1 {
+		// No more pages. End of pagination.
+		return nil, nil, nil
+	}
+	return c.epicIssues[epic], nil, nil
+}
+
+func (c *fakeClient) AssignEpicIssue(gid interface{}, epic, issue int, options ...glab.RequestOptionFunc) (*glab.EpicIssueAssignment, *glab.Response, error) {
+	r := &glab.Response{
+		Response: new(http.Response),
+	}
+	err := c.errors.assignEpicIssue
+	if err != nil {
+		r.StatusCode = http.StatusInternalServerError
+		return nil, r, err
+	}
+	for _, i := range c.epicIssues[epic] {
+		if i.ID == issue {
+			r.StatusCode = http.StatusConflict
+			return nil, r, fmt.Errorf("issue %d already assigned", issue)
+		}
+	}
+	for _, i := range c.issues {
+		if i.ID == issue {
+			if c.epicIssues == nil {
+				c.epicIssues = make(map[int][]*glab.Issue)
+			}
+			c.epicIssues[epic] = append(c.epicIssues[epic], i)
+			r.StatusCode = http.StatusCreated
+			return &glab.EpicIssueAssignment{Issue: i}, r, nil
+		}
+	}
+	return nil, nil, fmt.Errorf("issue %d not found", issue)
+}
+
+func (c *fakeClient) CreateEpicNote(gid interface{}, epic int, opt *glab.CreateEpicNoteOptions, options ...glab.RequestOptionFunc) (*glab.Note, *glab.Response, error) {
+	err := c.errors.createEpicNote
+	if err != nil {
+		return nil, nil, err
+	}
+	n := &glab.Note{ID: c.nextNoteID(), Body: *opt.Body}
+	c.epicNotes = append(c.epicNotes, n)
+	return n, nil, nil
+}
+
+func (c *fakeClient) ListGroupEpicDiscussions(
+	gid interface{},
+	epic int,
+	opt *glab.ListGroupEpicDiscussionsOptions,
+	options ...glab.RequestOptionFunc,
+) ([]*glab.Discussion, *glab.Response, error) {
+	return c.listDiscussions((*glab.ListOptions)(opt))
+}
+
+func (c *fakeClient) CreateEpicDiscussion(
+	gid interface{},
+	epic int,
+	opt *glab.CreateEpicDiscussionOptions,
+	options ...glab.RequestOptionFunc,
+) (*glab.Discussion, *glab.Response, error) {
+	return c.createDiscussion(*opt.Body)
+}
+
+func (c *fakeClient) AddEpicDiscussionNote(
+	gid interface{},
+	epic int,
+	discussion string,
+	opt *glab.AddEpicDiscussionNoteOptions,
+	options ...glab.RequestOptionFunc,
+) (*glab.Note, *glab.Response, error) {
+	return c.addDiscussionNote(discussion, *opt.Body)
+}
+
+func (c *fakeClient) ListGroupLabels(gid interface{}, opt *glab.ListGroupLabelsOptions, options ...glab.RequestOptionFunc) ([]*glab.GroupLabel, *glab.Response, error) {
+	err := c.errors.listGroupLabels
+	if err != nil {
+		return nil, nil, err
+	}
+	if opt != nil && opt.Page > 1 {
+		// No more pages. End of pagination.
+		return nil, nil, nil
+	}
+	return c.groupLabels, nil, nil
+}
+
+func (c *fakeClient) CreateGroupLabel(gid interface{}, opt *glab.CreateGroupLabelOptions, options ...glab.RequestOptionFunc) (*glab.GroupLabel, *glab.Response, error) {
+	r := &glab.Response{
+		Response: new(http.Response),
+	}
+	err := c.errors.createGroupLabel
+	if err != nil {
+		r.StatusCode = http.StatusInternalServerError
+		return nil, r, err
+	}
+	for _, l := range c.groupLabels {
+		if l.Name == *opt.Name {
+			r.StatusCode = http.StatusConflict
+			return nil, r, fmt.Errorf("label %q already exists", l.Name)
+		}
+	}
+	l := &glab.GroupLabel{ID: len(c.groupLabels), Name: *opt.Name, Color: *opt.Color}
+	c.groupLabels = append(c.groupLabels, l)
+	return l, r, nil
+}
+
+func (c *fakeClient) ListIssueBoards(pid interface{}, opt *glab.ListIssueBoardsOptions, options ...glab.RequestOptionFunc) ([]*glab.IssueBoard, *glab.Response, error) {
+	err := c.errors.listBoards
+	if err != nil {
+		return nil, nil, err
+	}
+	if opt != nil && opt.Page > 1 {
+		// No more pages. End of pagination.
+		return nil, nil, nil
+	}
+	return c.boards, nil, nil
+}
+
+func (c *fakeClient) CreateIssueBoard(pid interface{}, opt *glab.CreateIssueBoardOptions, options ...glab.RequestOptionFunc) (*glab.IssueBoard, *glab.Response, error) {
+	err := c.errors.createBoard
+	if err != nil {
+		return nil, nil, err
+	}
+	b := &glab.IssueBoard{ID: len(c.boards) + 1, Name: *opt.Name}
+	c.boards = append(c.boards, b)
+	return b, nil, nil
+}
+
+func (c *fakeClient) GetIssueBoardLists(pid interface{}, board int, opt *glab.GetIssueBoardListsOptions, options ...glab.RequestOptionFunc) ([]*glab.BoardList, *glab.Response, error) {
+	err := c.errors.getBoardLists
+	if err != nil {
+		return nil, nil, err
+	}
+	return c.boardLists[board], nil, nil
+}
+
+func (c *fakeClient) CreateIssueBoardList(pid interface{}, board int, opt *glab.CreateIssueBoardListOptions, options ...glab.RequestOptionFunc) (*glab.BoardList, *glab.Response, error) {
+	err := c.errors.createBoardList
+	if err != nil {
+		return nil, nil, err
+	}
+	if c.boardLists == nil {
+		c.boardLists = make(map[int][]*glab.BoardList)
+	}
+	l := &glab.BoardList{ID: len(c.boardLists[board]) + 1, Position: len(c.boardLists[board])}
+	switch {
+	case opt.LabelID != nil:
+		l.Label = &glab.Label{ID: *opt.LabelID}
+	case opt.MilestoneID != nil:
+		l.Milestone = &glab.Milestone{ID: *opt.MilestoneID}
+	case opt.AssigneeID != nil:
+		l.Assignee = &struct {
+			ID       int    `json:"id"`
+			Name     string `json:"name"`
+			Username string `json:"username"`
+		}{ID: *opt.AssigneeID}
+	}
+	c.boardLists[board] = append(c.boardLists[board], l)
+	return l, nil, nil
+}
+
+func (c *fakeClient) ListVariables(pid interface{}, opt *glab.ListProjectVariablesOptions, options ...glab.RequestOptionFunc) ([]*glab.ProjectVariable, *glab.Response, error) {
+	err := c.errors.listVariables
+	if err != nil {
+		return nil, nil, err
+	}
+	if opt != nil && opt.Page > 1 {
+		// No more pages. End of pagination.
+		return nil, nil, nil
+	}
+	return c.variables, nil, nil
+}
+
+func (c *fakeClient) CreateVariable(pid interface{}, opt *glab.CreateProjectVariableOptions, options ...glab.RequestOptionFunc) (*glab.ProjectVariable, *glab.Response, error) {
+	err := c.errors.createVariable
+	if err != nil {
+		return nil, nil, err
+	}
+	v := &glab.ProjectVariable{
+		Key:              *opt.Key,
+		Value:            *opt.Value,
+		VariableType:     *opt.VariableType,
+		Protected:        *opt.Protected,
+		Masked:           *opt.Masked,
+		Raw:              *opt.Raw,
+		EnvironmentScope: *opt.EnvironmentScope,
+	}
+	c.variables = append(c.variables, v)
+	return v, nil, nil
+}
+
+func (c *fakeClient) ListProjectHooks(pid interface{}, opt *glab.ListProjectHooksOptions, options ...glab.RequestOptionFunc) ([]*glab.ProjectHook, *glab.Response, error) {
+	err := c.errors.listProjectHooks
+	if err != nil {
+		return nil, nil, err
+	}
+	if opt != nil && opt.Page > 1 {
+		// No more pages. End of pagination.
+		return nil, nil, nil
+	}
+	return c.hooks, nil, nil
+}
+
+func (c *fakeClient) AddProjectHook(pid interface{}, opt *glab.AddProjectHookOptions, options ...glab.RequestOptionFunc) (*glab.ProjectHook, *glab.Response, error) {
+	err := c.errors.addProjectHook
+	if err != nil {
+		return nil, nil, err
+	}
+	h := &glab.ProjectHook{
+		ID:                     len(c.hooks) + 1,
+		URL:                    *opt.URL,
+		PushEvents:             *opt.PushEvents,
+		PushEventsBranchFilter: *opt.PushEventsBranchFilter,
+		IssuesEvents:           *opt.IssuesEvents,
+		MergeRequestsEvents:    *opt.MergeRequestsEvents,
+		NoteEvents:             *opt.NoteEvents,
+		PipelineEvents:         *opt.PipelineEvents,
+		EnableSSLVerification:  *opt.EnableSSLVerification,
+	}
+	c.hooks = append(c.hooks, h)
+	return h, nil, nil
+}
+
+func (c *fakeClient) ListIssueLabelEvents(pid interface{}, issue int, opt *glab.ListLabelEventsOptions, options ...glab.RequestOptionFunc) ([]*glab.LabelEvent, *glab.Response, error) {
+	err := c.errors.listLabelEvents
+	if err != nil {
+		return nil, nil, err
+	}
+	if opt != nil && opt.Page > 1 {
+		// No more pages. End of pagination.
+		return nil, nil, nil
+	}
+	return c.labelEvents, nil, nil
+}
+
+func (c *fakeClient) ListIssueMilestoneEvents(pid interface{}, issue int, opt *glab.ListMilestoneEventsOptions, options ...glab.RequestOptionFunc) ([]*glab.MilestoneEvent, *glab.Response, error) {
+	err := c.errors.listMilestoneEvents
+	if err != nil {
+		return nil, nil, err
+	}
+	if c.freeTier {
+		// Paid tiers API.
+		r := &glab.Response{Response: &http.Response{StatusCode: http.StatusNotFound}}
+		return nil, r, fmt.Errorf("404 Not Found")
+	}
+	if opt != nil && opt.Page > 1 {
+		// No more pages. End of pagination.
+		return nil, nil, nil
+	}
+	return c.milestoneEvents, nil, nil
+}
+
+func (c *fakeClient) ListIssueStateEvents(pid interface{}, issue int, opt *glab.ListStateEventsOptions, options ...glab.RequestOptionFunc) ([]*glab.StateEvent, *glab.Response, error) {
+	err := c.errors.listStateEvents
+	if err != nil {
+		return nil, nil, err
+	}
+	if opt != nil && opt.Page > 1 {
+		// No more pages. End of pagination.
+		return nil, nil, nil
+	}
+	return c.stateEvents, nil, nil
+}
+
+func (c *fakeClient) ListIssueWeightEvents(pid interface{}, issue int, opt *glab.ListWeightEventsOptions, options ...glab.RequestOptionFunc) ([]*glab.WeightEvent, *glab.Response, error) {
+	err := c.errors.listWeightEvents
+	if err != nil {
+		return nil, nil, err
+	}
+	if c.freeTier {
+		// Paid tiers API.
+		r := &glab.Response{Response: &http.Response{StatusCode: http.StatusNotFound}}
+		return nil, r, fmt.Errorf("404 Not Found")
+	}
+	if opt != nil && opt.Page > 1 {
+		// No more pages. End of pagination.
+		return nil, nil, nil
+	}
+	return c.weightEvents, nil, nil
+}
+
+func (c *fakeClient) ListProtectedBranches(pid interface{}, opt *glab.ListProtectedBranchesOptions, options ...glab.RequestOptionFunc) ([]*glab.ProtectedBranch, *glab.Response, error) {
+	err := c.errors.listProtectedBranches
+	if err != nil {
+		return nil, nil, err
+	}
+	if opt != nil && opt.Page > 1 {
+		// No more pages. End of pagination.
+		return nil, nil, nil
+	}
+	return c.protectedBranches, nil, nil
+}
+
+func (c *fakeClient) ListProtectedBranchDeployKeys(pid interface{}, opt *glab.ListProtectedBranchesOptions, options ...glab.RequestOptionFunc) (map[int]int, *glab.Response, error) {
+	err := c.errors.listProtectedBranchDeployKeys
+	if err != nil {
+		return nil, nil, err
+	}
+	return c.branchDeployKeys, nil, nil
+}
+
+// branchAccess converts the access options of a protected branch. Deploy key
+// entries are decoded as Maintainer roles, as go-gitlab does.
+func (c *fakeClient) branchAccess(level *glab.AccessLevelValue, allowed *[]*glab.BranchPermissionOptions) []*glab.BranchAccessDescription {
+	ds := make([]*glab.BranchAccessDescription, 0)
+	if level != nil {
+		ds = append(ds, &glab.BranchAccessDescription{AccessLevel: *level})
+	}
+	if allowed != nil {
+		for _, p := range *allowed {
+			d := new(glab.BranchAccessDescription)
+			switch {
+			case p.DeployKeyID != nil:
+				if c.branchDeployKeys == nil {
+					c.branchDeployKeys = make(map[int]int)
+				}
+				d.ID = 100 + len(c.branchDeployKeys)
+				d.AccessLevel = glab.MaintainerPermissions
+				c.branchDeployKeys[d.ID] = *p.DeployKeyID
+			case p.UserID != nil:
+				d.UserID = *p.UserID
+			case p.GroupID != nil:
+				d.GroupID = *p.GroupID
+			default:
+				d.AccessLevel = *p.AccessLevel
+			}
+			ds = append(ds, d)
+		}
+	}
+	return ds
+}
+
+func (c *fakeClient) ProtectRepositoryBranches(pid interface{}, opt *glab.ProtectRepositoryBranchesOptions, options ...glab.RequestOptionFunc) (*glab.ProtectedBranch, *glab.Response, error) {
+	err := c.errors.protectBranch
+	if err != nil {
+		return nil, nil, err
+	}
+	r := &glab.Response{
+		Response: new(http.Response),
+	}
+	for _, pb := range c.protectedBranches {
+		if pb.Name == *opt.Name {
+			r.StatusCode = http.StatusConflict
+			return nil, r, fmt.Errorf("branch %q already protected", pb.Name)
+		}
+	}
+	pb := &glab.ProtectedBranch{
+		ID:                    len(c.protectedBranches) + 1,
+		Name:                  *opt.Name,
+		PushAccessLevels:      c.branchAccess(opt.PushAccessLevel, opt.AllowedToPush),
+		MergeAccessLevels:     c.branchAccess(opt.MergeAccessLevel, opt.AllowedToMerge),
+		UnprotectAccessLevels: c.branchAccess(opt.UnprotectAccessLevel, opt.AllowedToUnprotect),
+	}
+	if opt.AllowForcePush != nil {
+		pb.AllowForcePush = *opt.AllowForcePush
+	}
+	if opt.CodeOwnerApprovalRequired != nil {
+		pb.CodeOwnerApprovalRequired = *opt.CodeOwnerApprovalRequired
+	}
+	c.protectedBranches = append(c.protectedBranches, pb)
+	return pb, nil, nil
+}
+
+func (c *fakeClient) UnprotectRepositoryBranches(pid interface{}, branch string, options ...glab.RequestOptionFunc) (*glab.Response, error) {
+	err := c.errors.unprotectBranch
+	if err != nil {
+		return nil, err
+	}
+	for k, pb := range c.protectedBranches {
+		if pb.Name == branch {
+			c.protectedBranches = append(c.protectedBranches[:k], c.protectedBranches[k+1:]...)
+			return nil, nil
+		}
+	}
+	return nil, fmt.Errorf("branch %q not protected", branch)
+}
+
+func (c *fakeClient) ListProtectedTags(pid interface{}, opt *glab.ListProtectedTagsOptions, options ...glab.RequestOptionFunc) ([]*glab.ProtectedTag, *glab.Response, error) {
+	err := c.errors.listProtectedTags
+	if err != nil {
+		return nil, nil, err
+	}
+	if opt != nil && opt.Page > 1 {
+		// No more pages. End of pagination.
+		return nil, nil, nil
+	}
+	return c.protectedTags, nil, nil
+}
+
+func (c *fakeClient) ProtectRepositoryTags(pid interface{}, opt *glab.ProtectRepositoryTagsOptions, options ...glab.RequestOptionFunc) (*glab.ProtectedTag, *glab.Response, error) {
+	err := c.errors.protectTag
+	if err != nil {
+		return nil, nil, err
+	}
+	r := &glab.Response{
+		Response: new(http.Response),
+	}
+	for _, pt := range c.protectedTags {
+		if pt.Name == *opt.Name {
+			r.StatusCode = http.StatusConflict
+			return nil, r, fmt.Errorf("tag %q already protected", pt.Name)
+		}
+	}
+	pt := &glab.ProtectedTag{Name: *opt.Name}
+	if opt.CreateAccessLevel != nil {
+		pt.CreateAccessLevels = append(pt.CreateAccessLevels, &glab.TagAccessDescription{AccessLevel: *opt.CreateAccessLevel})
+	}
+	if opt.AllowedToCreate != nil {
+		for _, p := range *opt.AllowedToCreate {
+			d := new(glab.TagAccessDescription)
+			switch {
+			case p.UserID != nil:
+				d.UserID = *p.UserID
+			case p.GroupID != nil:
+				d.GroupID = *p.GroupID
+			default:
+				d.AccessLevel = *p.AccessLevel
+			}
+			pt.CreateAccessLevels = append(pt.CreateAccessLevels, d)
+		}
+	}
+	c.protectedTags = append(c.protectedTags, pt)
+	return pt, nil, nil
+}
+
+func (c *fakeClient) UnprotectRepositoryTags(pid interface{}, tag string, options ...glab.RequestOptionFunc) (*glab.Response, error) {
+	err := c.errors.unprotectTag
+	if err != nil {
+		return nil, err
+	}
+	for k, pt := range c.protectedTags {
+		if pt.Name == tag {
+			c.protectedTags = append(c.protectedTags[:k], c.protectedTags[k+1:]...)
+			return nil, nil
+		}
+	}
+	return nil, fmt.Errorf("tag %q not protected", tag)
+}
+
+func (c *fakeClient) GetProjectApprovalRules(pid interface{}, opt *glab.GetProjectApprovalRulesListsOptions, options ...glab.RequestOptionFunc) ([]*glab.ProjectApprovalRule, *glab.Response, error) {
+	err := c.errors.listApprovalRules
+	if err != nil {
+		return nil, nil, err
+	}
+	if opt != nil && opt.Page > 1 {
+		// No more pages. End of pagination.
+		return nil, nil, nil
+	}
+	return c.approvalRules, nil, nil
+}
+
+func (c *fakeClient) CreateProjectApprovalRule(pid interface{}, opt *glab.CreateProjectLevelRuleOptions, options ...glab.RequestOptionFunc) (*glab.ProjectApprovalRule, *glab.Response, error) {
+	err := c.errors.createApprovalRule
+	if err != nil {
+		return nil, nil, err
+	}
+	r := &glab.ProjectApprovalRule{
+		ID:                            len(c.approvalRules) + 1,
+		Name:                          *opt.Name,
+		ApprovalsRequired:             *opt.ApprovalsRequired,
+		AppliesToAllProtectedBranches: *opt.AppliesToAllProtectedBranches,
+	}
+	if opt.RuleType != nil {
+		r.RuleType = *opt.RuleType
+	}
+	for _, id := range *opt.UserIDs {
+		for _, u := range c.users {
+			if u.ID == id {
+				r.Users = append(r.Users, &glab.BasicUser{ID: u.ID, Username: u.Username})
+			}
+		}
+	}
+	for _, id := range *opt.GroupIDs {
+		for _, g := range c.groups {
+			if g.ID == id {
+				r.Groups = append(r.Groups, g)
+			}
+		}
+	}
+	for _, id := range *opt.ProtectedBranchIDs {
+		for _, pb := range c.protectedBranches {
+			if pb.ID == id {
+				r.ProtectedBranches = append(r.ProtectedBranches, pb)
+			}
+		}
+	}
+	c.approvalRules = append(c.approvalRules, r)
+	return r, nil, nil
+}
+
+func (c *fakeClient) ListProjectFeatureFlags(pid interface{}, opt *glab.ListProjectFeatureFlagOptions, options ...glab.RequestOptionFunc) ([]*glab.ProjectFeatureFlag, *glab.Response, error) {
+	err := c.errors.listFeatureFlags
+	if err != nil {
+		return nil, nil, err
+	}
+	if opt != nil && opt.Page > 1 {
+		// No more pages. End of pagination.
+		return nil, nil, nil
+	}
+	return c.featureFlags, nil, nil
+}
+
+func (c *fakeClient) CreateProjectFeatureFlag(pid interface{}, opt *glab.CreateProjectFeatureFlagOptions, options ...glab.RequestOptionFunc) (*glab.ProjectFeatureFlag, *glab.Response, error) {
+	err := c.errors.createFeatureFlag
+	if err != nil {
+		return nil, nil, err
+	}
+	f := &glab.ProjectFeatureFlag{
+		Name:        *opt.Name,
+		Description: *opt.Description,
+		Active:      *opt.Active,
+	}
+	if opt.Version != nil {
+		f.Version = *opt.Version
+	}
+	for _, st := range *opt.Strategies {
+		f.Strategies = append(f.Strategies, &glab.ProjectFeatureFlagStrategy{
+			Name:       *st.Name,
+			Parameters: st.Parameters,
+			Scopes:     *st.Scopes,
+		})
+	}
+	c.featureFlags = append(c.featureFlags, f)
+	return f, nil, nil
+}
+
+func (c *fakeClient) ListEnvironments(pid interface{}, opt *glab.ListEnvironmentsOptions, options ...glab.RequestOptionFunc) ([]*glab.Environment, *glab.Response, error) {
+	err := c.errors.listEnvironments
+	if err != nil {
+		return nil, nil, err
+	}
+	if opt != nil && opt.Page > 1 {
+		// No more pages. End of pagination.
+		return nil, nil, nil
+	}
+	return c.environments, nil, nil
+}
+
+func (c *fakeClient) CreateEnvironment(pid interface{}, opt *glab.CreateEnvironmentOptions, options ...glab.RequestOptionFunc) (*glab.Environment, *glab.Response, error) {
+	err := c.errors.createEnvironment
+	if err != nil {
+		return nil, nil, err
+	}
+	e := &glab.Environment{
+		ID:   len(c.environments) + 1,
+		Name: *opt.Name,
+	}
+	if opt.ExternalURL != nil {
+		e.ExternalURL = *opt.ExternalURL
+	}
+	if opt.Tier != nil {
+		e.Tier = *opt.Tier
+	}
+	c.environments = append(c.environments, e)
+	return e, nil, nil
+}
+
+func (c *fakeClient) ListFreezePeriods(pid interface{}, opt *glab.ListFreezePeriodsOptions, options ...glab.RequestOptionFunc) ([]*glab.FreezePeriod, *glab.Response, error) {
+	err := c.errors.listFreezePeriods
+	if err != nil {
+		return nil, nil, err
+	}
+	if opt != nil && opt.Page > 1 {
+		// No more pages. End of pagination.
+		return nil, nil, nil
+	}
+	return c.freezePeriods, nil, nil
+}
+
+func (c *fakeClient) CreateFreezePeriod(pid interface{}, opt *glab.CreateFreezePeriodOptions, options ...glab.RequestOptionFunc) (*glab.FreezePeriod, *glab.Response, error) {
+	err := c.errors.createFreezePeriod
+	if err != nil {
+		return nil, nil, err
+	}
+	fp := &glab.FreezePeriod{
+		ID:          len(c.freezePeriods) + 1,
+		FreezeStart: *opt.FreezeStart,
+		FreezeEnd:   *opt.FreezeEnd,
+	}
+	if opt.CronTimezone != nil {
+		fp.CronTimezone = *opt.CronTimezone
+	}
+	c.freezePeriods = append(c.freezePeriods, fp)
+	return fp, nil, nil
+}
+
+func (c *fakeClient) ListProjectBadges(pid interface{}, opt *glab.ListProjectBadgesOptions, options ...glab.RequestOptionFunc) ([]*glab.ProjectBadge, *glab.Response, error) {
+	err := c.errors.listBadges
+	if err != nil {
+		return nil, nil, err
+	}
+	if opt != nil && opt.Page > 1 {
+		// No more pages. End of pagination.
+		return nil, nil, nil
+	}
+	return c.badges, nil, nil
+}
+
+func (c *fakeClient) AddProjectBadge(pid interface{}, opt *glab.AddProjectBadgeOptions, options ...glab.RequestOptionFunc) (*glab.ProjectBadge, *glab.Response, error) {
+	err := c.errors.addBadge
+	if err != nil {
+		return nil, nil, err
+	}
+	b := &glab.ProjectBadge{
+		ID:       len(c.badges) + 1,
+		LinkURL:  *opt.LinkURL,
+		ImageURL: *opt.ImageURL,
+		Kind:     "project",
+	}
+	if opt.Name != nil {
+		b.Name = *opt.Name
+	}
+	c.badges = append(c.badges, b)
+	return b, nil, nil
+}
@@ -50,3 +50,209 @@ to:
     token: desttoken
     project: dest/project
 `
+
+const cfg5 = `
+from:
+    url: https://gitlab.mydomain.com
+    token: sourcetoken
+    project: source/project
+    mergeRequests: true
+to:
+    url: https://gitlab.mydomain.com
+    token: desttoken
+    project: dest/project
+`
+
+const cfg6 = `
+from:
+    url: https://gitlab.mydomain.com
+    token: sourcetoken
+    project: source/project
+    wiki: true
+to:
+    url: https://gitlab.mydomain.com
+    token: desttoken
+    project: dest/project
+`
+
+const cfg7 = `
+from:
+    url: https://gitlab.mydomain.com
+    token: sourcetoken
+    project: source/project
+    snippets: true
+to:
+    url: https://gitlab.mydomain.com
+    token: desttoken
+    project: dest/project
+`
+
+const cfg8 = `
+from:
+    url: https://gitlab.mydomain.com
+    token: sourcetoken
+    project: source/project
+    releases: true
+to:
+    url: https://gitlab.mydomain.com
+    token: desttoken
+    project: dest/project
+`
+
+const cfg9 = `
+from:
+    url: https://gitlab.mydomain.com
+    token: sourcetoken
+    project: source/project
+    members: true
+to:
+    url: https://gitlab.mydomain.com
+    token: desttoken
+    project: dest/project
+`
+
+const cfg10 = `
+from:
+    url: https://gitlab.mydomain.com
+    token: sourcetoken
+    project: source/project
+    group: source
+to:
+    url: https://gitlab.mydomain.com
+    token: desttoken
+    project: dest/project
+    group: dest
+`
+
+const cfg11 = `
+from:
+    url: https://gitlab.mydomain.com
+    token: sourcetoken
+    project: source/project
+    boards: true
+    labelsOnly: true
+to:
+    url: https://gitlab.mydomain.com
+    token: desttoken
+    project: dest/project
+`
+
+const cfg12 = `
+from:
+    url: https://gitlab.mydomain.com
+    token: sourcetoken
+    project: source/project
+    variables: true
+to:
+    url: https://gitlab.mydomain.com
+    token: desttoken
+    project: dest/project
+`
+
+const cfg13 = `
+from:
+    url: https://gitlab.mydomain.com
+    token: sourcetoken
+    project: source/project
+    hooks: true
+    hookHosts:
+        ci.old.com: ci.new.com
+to:
+    url: https://gitlab.mydomain.com
+    token: desttoken
+    project: dest/project
+`
+
+const cfg14 = `
+from:
+    url: https://gitlab.mydomain.com
+    token: sourcetoken
+    project: source/project
+    labelsOnly: true
+    settings:
+    - description
+    - avatar
+    - mergeSettings
+to:
+    url: https://gitlab.mydomain.com
+    token: desttoken
+    project: dest/project
+`
+
+const cfg15 = `
+from:
+    url: https://gitlab.mydomain.com
+    token: sourcetoken
+    project: source/project
+    group: source
+    protectedRefs: true
+to:
+    url: https://gitlab.mydomain.com
+    token: desttoken
+    project: dest/project
+    group: dest
+`
+
+const cfg16 = `
+from:
+    url: https://gitlab.mydomain.com
+    token: sourcetoken
+    project: source/project
+    group: source
+    protectedRefs: true
+    approvalRules: true
+to:
+    url: https://gitlab.mydomain.com
+    token: desttoken
+    project: dest/project
+    group: dest
+`
+
+const cfg17 = `
+from:
+    url: https://gitlab.mydomain.com
+    token: sourcetoken
+    project: source/project
+    featureFlags: true
+to:
+    url: https://gitlab.mydomain.com
+    token: desttoken
+    project: dest/project
+`
+
+const cfg18 = `
+from:
+    url: https://gitlab.mydomain.com
+    token: sourcetoken
+    project: source/project
+    environments: true
+to:
+    url: https://gitlab.mydomain.com
+    token: desttoken
+    project: dest/project
+`
+
+const cfg19 = `
+from:
+    url: https://gitlab.mydomain.com
+    token: sourcetoken
+    project: source/project
+    badges: true
+to:
+    url: https://gitlab.myotherdomain.com
+    token: desttoken
+    project: dest/project
+`
+
+const cfg20 = `
+from:
+    url: https://gitlab.mydomain.com
+    token: sourcetoken
+    project: source/project
+    boards: true
+    milestonesOnly: true
+to:
+    url: https://gitlab.mydomain.com
+    token: desttoken
+    project: dest/project
+`
@@ -0,0 +1,293 @@
+package migration
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gotsunami/gitlab-copy/gitlab"
+	glab "github.com/xanzy/go-gitlab"
+)
+
+// threadWriter writes discussions (threads of notes) to a target issue or
+// merge request.
+type threadWriter interface {
+	// note writes a standalone note.
+	note(c gitlab.GitLaber, body string) (*glab.Note, *glab.Response, error)
+	// start starts a new thread.
+	start(c gitlab.GitLaber, body string) (*glab.Discussion, *glab.Response, error)
+	// reply adds a note to the thread id.
+	reply(c gitlab.GitLaber, id, body string) (*glab.Note, *glab.Response, error)
+	// resolve marks the thread id as resolved.
+	resolve(id string) error
+	// String describes the target, for use in messages.
+	String() string
+}
+
+// issueThreads writes discussions to a target issue.
+type issueThreads struct {
+	pid, iid int
+}
+
+func (w *issueThreads) note(c gitlab.GitLaber, body string) (*glab.Note, *glab.Response, error) {
+	return c.CreateIssueNote(w.pid, w.iid, &glab.CreateIssueNoteOptions{Body: &body})
+}
+
+func (w *issueThreads) start(c gitlab.GitLaber, body string) (*glab.Discussion, *glab.Response, error) {
+	return c.CreateIssueDiscussion(w.pid, w.iid, &glab.CreateIssueDiscussionOptions{Body: &body})
+}
+
+func (w *issueThreads) reply(c gitlab.GitLaber, id, body string) (*glab.Note, *glab.Response, error) {
+	return c.AddIssueDiscussionNote(w.pid, w.iid, id, &glab.AddIssueDiscussionNoteOptions{Body: &body})
+}
+
+// resolve is a no-op: the GitLab API doesn't allow resolving issue threads.
+func (w *issueThreads) resolve(id string) error {
+	return nil
+}
+
+func (w *issueThreads) String() string {
+	return fmt.Sprintf("issue #%d", w.iid)
+}
+
+// mergeRequestThreads writes discussions to a target merge request.
+type mergeRequestThreads struct {
+	target   gitlab.GitLaber
+	pid, iid int
+}
+
+func (w *mergeRequestThreads) note(c gitlab.GitLaber, body string) (*glab.Note, *glab.Response, error) {
+	return c.CreateMergeRequestNote(w.pid, w.iid, &glab.CreateMergeRequestNoteOptions{Body: &body})
+}
+
+func (w *mergeRequestThreads) start(c gitlab.GitLaber, body string) (*glab.Discussion, *glab.Response, error) {
+	return c.CreateMergeRequestDiscussion(w.pid, w.iid, &glab.CreateMergeRequestDiscussionOptions{Body: &body})
+}
+
+func (w *mergeRequestThreads) reply(c gitlab.GitLaber, id, body string) (*glab.Note, *glab.Response, error) {
+	return c.AddMergeRequestDiscussionNote(w.pid, w.iid, id, &glab.AddMergeRequestDiscussionNoteOptions{Body: &body})
+}
+
+func (w *mergeRequestThreads) resolve(id string) error {
+	resolved := true
+	_, _, err := w.target.ResolveMergeRequestDiscussion(w.pid, w.iid, id,
+		&glab.ResolveMergeRequestDiscussionOptions{Resolved: &resolved})
+	return err
+}
+
+func (w *mergeRequestThreads) String() string {
+	return fmt.Sprintf("merge request !%d", w.iid)
+}
+
+// snippetThreads writes discussions to a target snippet.
+type snippetThreads struct {
+	pid, id int
+}
+
+func (w *snippetThreads) note(c gitlab.GitLaber, body string) (*glab.Note, *glab.Response, error) {
+	return c.CreateSnippetNote(w.pid, w.id, &glab.CreateSnippetNoteOptions{Body: &body})
+}
+
+func (w *snippetThreads) start(c gitlab.GitLaber, body string) (*glab.Discussion, *glab.Response, error) {
+	return c.CreateSnippetDiscussion(w.pid, w.id, &glab.CreateSnippetDiscussionOptions{Body: &body})
+}
+
+func (w *snippetThreads) reply(c gitlab.GitLaber, id, body string) (*glab.Note, *glab.Response, error) {
+	return c.AddSnippetDiscussionNote(w.pid, w.id, id, &glab.AddSnippetDiscussionNoteOptions{Body: &body})
+}
+
+// resolve is a no-op: snippet threads can't be resolved.
+func (w *snippetThreads) resolve(id string) error {
+	return nil
+}
+
+func (w *snippetThreads) String() string {
+	return fmt.Sprintf("snippet $%d", w.id)
+}
+
+// epicThreads writes discussions to a target epic.
+type epicThreads struct {
+	gid string
+	id  int
+}
+
+func (w *epicThreads) note(c gitlab.GitLaber, body string) (*glab.Note, *glab.Response, error) {
+	return c.CreateEpicNote(w.gid, w.id, &glab.CreateEpicNoteOptions{Body: &body})
+}
+
+func (w *epicThreads) start(c gitlab.GitLaber, body string) (*glab.Discussion, *glab.Response, error) {
+	return c.CreateEpicDiscussion(w.gid, w.id, &glab.CreateEpicDiscussionOptions{Body: &body})
+}
+
+func (w *epicThreads) reply(c gitlab.GitLaber, id, body string) (*glab.Note, *glab.Response, error) {
+	return c.AddEpicDiscussionNote(w.gid, w.id, id, &glab.AddEpicDiscussionNoteOptions{Body: &body})
+}
+
+// resolve is a no-op: epic threads can't be resolved.
+func (w *epicThreads) resolve(id string) error {
+	return nil
+}
+
+func (w *epicThreads) String() string {
+	return fmt.Sprintf("epic %d", w.id)
+}
+
+// withShorterBody calls write with body. If the target complains about the
+// body's length, write is called once again with a shorter body.
+func withShorterBody(body string, write func(string) (*glab.Response, error)) error {
+	resp, err := write(body)
+	if err != nil && resp != nil && resp.StatusCode == http.StatusRequestURITooLong {
+		fmt.Printf("target: note's body too long, shortening it ...\n")
+		if len(body) > 1024 {
+			body = body[:1024]
+		}
+		_, err = write(body)
+	}
+	return err
+}
+
+// isResolved returns true if the discussion can be resolved and all its
+// resolvable notes are resolved.
+func isResolved(d *glab.Discussion) bool {
+	resolvable := false
+	for _, n := range d.Notes {
+		if !n.Resolvable {
+			continue
+		}
+		if !n.Resolved {
+			return false
+		}
+		resolvable = true
+	}
+	return resolvable
+}
+
+// copyDiscussions writes source discussions ds with w, in the order returned
+// by the GitLab API (oldest first). Threads are recreated as threads with
+// their replies, and resolved threads are marked as such when possible. The
+// IDs of the source notes are returned mapped to the IDs of the target notes.
+func (m *Migration) copyDiscussions(w threadWriter, ds []*glab.Discussion) (map[int]int, error) {
+	ids := make(map[int]int)
+	for _, d := range ds {
+		if len(d.Notes) == 0 {
+			continue
+		}
+		first := d.Notes[0]
+		c, body := m.noteWriter(first)
+		if d.IndividualNote {
+			err := withShorterBody(body, func(b string) (*glab.Response, error) {
+				n, resp, err := w.note(c, b)
+				if err == nil {
+					ids[first.ID] = n.ID
+				}
+				return resp, err
+			})
+			if err != nil {
+				return nil, fmt.Errorf("target: error creating note for %s: %s", w, err.Error())
+			}
+			continue
+		}
+		var id string
+		err := withShorterBody(body, func(b string) (*glab.Response, error) {
+			td, resp, err := w.start(c, b)
+			if err == nil {
+				id = td.ID
+				if len(td.Notes) > 0 {
+					ids[first.ID] = td.Notes[0].ID
+				}
+			}
+			return resp, err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("target: error creating thread for %s: %s", w, err.Error())
+		}
+		for _, n := range d.Notes[1:] {
+			c, body := m.noteWriter(n)
+			src := n.ID
+			err := withShorterBody(body, func(b string) (*glab.Response, error) {
+				tn, resp, err := w.reply(c, id, b)
+				if err == nil {
+					ids[src] = tn.ID
+				}
+				return resp, err
+			})
+			if err != nil {
+				return nil, fmt.Errorf("target: error replying to thread for %s: %s", w, err.Error())
+			}
+		}
+		if isResolved(d) {
+			if err := w.resolve(id); err != nil {
+				return nil, fmt.Errorf("target: error resolving thread for %s: %s", w, err.Error())
+			}
+		}
+	}
+	return ids, nil
+}
+
+// listDiscussions returns all discussions returned by list, fetching all
+// pages.
+func listDiscussions(list func(*glab.ListOptions) ([]*glab.Discussion, error)) ([]*glab.Discussion, error) {
+	all := make([]*glab.Discussion, 0)
+	opts := &glab.ListOptions{PerPage: ResultsPerPage, Page: 1}
+	for {
+		ds, err := list(opts)
+		if err != nil {
+			return nil, err
+		}
+		if len(ds) == 0 {
+			break
+		}
+		all = append(all, ds...)
+		opts.Page++
+	}
+	return all, nil
+}
+
+// issueDiscussions returns all discussions of the source issue iid.
+func (m *Migration) issueDiscussions(iid int) ([]*glab.Discussion, error) {
+	ds, err := listDiscussions(func(lo *glab.ListOptions) ([]*glab.Discussion, error) {
+		ds, _, err := m.Endpoint.SrcClient.ListIssueDiscussions(m.srcProject.ID, iid, (*glab.ListIssueDiscussionsOptions)(lo))
+		return ds, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("source: can't get issue #%d discussions: %s", iid, err.Error())
+	}
+	return ds, nil
+}
+
+// mergeRequestDiscussions returns all discussions of the source merge request
+// iid.
+func (m *Migration) mergeRequestDiscussions(iid int) ([]*glab.Discussion, error) {
+	ds, err := listDiscussions(func(lo *glab.ListOptions) ([]*glab.Discussion, error) {
+		ds, _, err := m.Endpoint.SrcClient.ListMergeRequestDiscussions(m.srcProject.ID, iid, (*glab.ListMergeRequestDiscussionsOptions)(lo))
+		return ds, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("source: can't get merge request !%d discussions: %s", iid, err.Error())
+	}
+	return ds, nil
+}
+
+// snippetDiscussions returns all discussions of the source snippet id.
+func (m *Migration) snippetDiscussions(id int) ([]*glab.Discussion, error) {
+	ds, err := listDiscussions(func(lo *glab.ListOptions) ([]*glab.Discussion, error) {
+		ds, _, err := m.Endpoint.SrcClient.ListSnippetDiscussions(m.srcProject.ID, id, (*glab.ListSnippetDiscussionsOptions)(lo))
+		return ds, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("source: can't get snippet $%d discussions: %s", id, err.Error())
+	}
+	return ds, nil
+}
+
+// epicDiscussions returns all discussions of the source epic id.
+func (m *Migration) epicDiscussions(id int) ([]*glab.Discussion, error) {
+	gid := m.params.SrcPrj.Group
+	ds, err := listDiscussions(func(lo *glab.ListOptions) ([]*glab.Discussion, error) {
+		ds, _, err := m.Endpoint.SrcClient.ListGroupEpicDiscussions(gid, id, (*glab.ListGroupEpicDiscussionsOptions)(lo))
+		return ds, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("source: can't get epic %d discussions: %s", id, err.Error())
+	}
+	return ds, nil
+}
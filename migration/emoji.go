@@ -0,0 +1,133 @@
+package migration
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gotsunami/gitlab-copy/gitlab"
+	glab "github.com/xanzy/go-gitlab"
+)
+
+// listAwardEmoji returns all award emoji returned by list, fetching all
+// pages.
+func listAwardEmoji(list func(*glab.ListAwardEmojiOptions) ([]*glab.AwardEmoji, error)) ([]*glab.AwardEmoji, error) {
+	all := make([]*glab.AwardEmoji, 0)
+	opts := &glab.ListAwardEmojiOptions{PerPage: ResultsPerPage, Page: 1}
+	for {
+		awards, err := list(opts)
+		if err != nil {
+			return nil, err
+		}
+		if len(awards) == 0 {
+			break
+		}
+		all = append(all, awards...)
+		opts.Page++
+	}
+	return all, nil
+}
+
+// awardEmoji awards emoji with the token of their user, when available. The
+// emoji which can't be awarded that way are returned.
+func (m *Migration) awardEmoji(awards []*glab.AwardEmoji, award func(gitlab.GitLaber, string) (*glab.Response, error)) ([]*glab.AwardEmoji, error) {
+	left := make([]*glab.AwardEmoji, 0)
+	for _, a := range awards {
+		c, ok := m.toUsers[a.User.Username]
+		if !ok {
+			left = append(left, a)
+			continue
+		}
+		resp, err := award(c, a.Name)
+		if err != nil {
+			// GitLab returns a 409 code if the emoji has already been awarded
+			if resp != nil && resp.StatusCode == http.StatusConflict {
+				continue
+			}
+			return nil, err
+		}
+	}
+	return left, nil
+}
+
+// summarizeAwardEmoji returns one markdown list item per emoji, along with
+// the users who awarded it. suffix is appended to each item.
+func summarizeAwardEmoji(awards []*glab.AwardEmoji, suffix string) []string {
+	names := make([]string, 0)
+	users := make(map[string][]string)
+	for _, a := range awards {
+		if _, ok := users[a.Name]; !ok {
+			names = append(names, a.Name)
+		}
+		users[a.Name] = append(users[a.Name], "@"+a.User.Username)
+	}
+	items := make([]string, len(names))
+	for k, name := range names {
+		items[k] = fmt.Sprintf("- :%s: %d (%s)%s", name, len(users[name]), strings.Join(users[name], ", "), suffix)
+	}
+	return items
+}
+
+// copyIssueAwardEmoji copies the award emoji of the source issue srcIID and
+// of its notes to the target issue dstIID. notes maps the IDs of the source
+// notes found in ds to the IDs of the target notes. Emoji whose user has no
+// token are summarized in a note, so that votes don't get lost.
+func (m *Migration) copyIssueAwardEmoji(srcIID, dstIID int, ds []*glab.Discussion, notes map[int]int) error {
+	source := m.Endpoint.SrcClient
+	target := m.Endpoint.DstClient
+
+	srcProjectID := m.srcProject.ID
+	tarProjectID := m.dstProject.ID
+
+	awards, err := listAwardEmoji(func(opts *glab.ListAwardEmojiOptions) ([]*glab.AwardEmoji, error) {
+		awards, _, err := source.ListIssueAwardEmoji(srcProjectID, srcIID, opts)
+		return awards, err
+	})
+	if err != nil {
+		return fmt.Errorf("source: can't get issue #%d award emoji: %s", srcIID, err.Error())
+	}
+	left, err := m.awardEmoji(awards, func(c gitlab.GitLaber, name string) (*glab.Response, error) {
+		_, resp, err := c.CreateIssueAwardEmoji(tarProjectID, dstIID, &glab.CreateAwardEmojiOptions{Name: name})
+		return resp, err
+	})
+	if err != nil {
+		return fmt.Errorf("target: error awarding emoji to issue #%d: %s", dstIID, err.Error())
+	}
+	summary := summarizeAwardEmoji(left, "")
+
+	for _, d := range ds {
+		for _, n := range d.Notes {
+			tid, ok := notes[n.ID]
+			if !ok {
+				continue
+			}
+			awards, err := listAwardEmoji(func(opts *glab.ListAwardEmojiOptions) ([]*glab.AwardEmoji, error) {
+				awards, _, err := source.ListIssuesAwardEmojiOnNote(srcProjectID, srcIID, n.ID, opts)
+				return awards, err
+			})
+			if err != nil {
+				return fmt.Errorf("source: can't get issue #%d note award emoji: %s", srcIID, err.Error())
+			}
+			left, err := m.awardEmoji(awards, func(c gitlab.GitLaber, name string) (*glab.Response, error) {
+				_, resp, err := c.CreateIssuesAwardEmojiOnNote(tarProjectID, dstIID, tid, &glab.CreateAwardEmojiOptions{Name: name})
+				return resp, err
+			})
+			if err != nil {
+				return fmt.Errorf("target: error awarding emoji to issue #%d note: %s", dstIID, err.Error())
+			}
+			suffix := fmt.Sprintf(" on the comment of @%s from %s", n.Author.Username, n.CreatedAt.Format(time.RFC1123))
+			summary = append(summary, summarizeAwardEmoji(left, suffix)...)
+		}
+	}
+
+	if len(summary) == 0 {
+		return nil
+	}
+	body := fmt.Sprintf("Award emoji of the original issue:\n\n%s", strings.Join(summary, "\n"))
+	opts := &glab.CreateIssueNoteOptions{Body: &body}
+	if _, _, err := target.CreateIssueNote(tarProjectID, dstIID, opts); err != nil {
+		return fmt.Errorf("target: error adding award emoji note to issue #%d: %s", dstIID, err.Error())
+	}
+	return nil
+}
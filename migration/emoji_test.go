@@ -0,0 +1,115 @@
+package migration
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/gotsunami/gitlab-copy/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	glab "github.com/xanzy/go-gitlab"
+)
+
+func TestCopyIssueAwardEmoji(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	runs := []struct {
+		name    string
+		setup   func(src, dst, alice *fakeClient)
+		asserts func(err error, src, dst, alice *fakeClient)
+	}{
+		{
+			"No award emoji",
+			func(src, dst, alice *fakeClient) {},
+			func(err error, src, dst, alice *fakeClient) {
+				assert.NoError(err)
+				assert.Empty(dst.issueNotes)
+			},
+		},
+		{
+			"Award emoji with user tokens",
+			func(src, dst, alice *fakeClient) {
+				src.awardEmoji = makeAwardEmoji("thumbsup:alice")
+				src.noteAwardEmoji = map[int][]*glab.AwardEmoji{1: makeAwardEmoji("heart:alice")}
+			},
+			func(err error, src, dst, alice *fakeClient) {
+				assert.NoError(err)
+				if assert.Len(alice.awardEmoji, 1) {
+					assert.Equal("thumbsup", alice.awardEmoji[0].Name)
+				}
+				if assert.Len(alice.noteAwardEmoji[10], 1) {
+					assert.Equal("heart", alice.noteAwardEmoji[10][0].Name)
+				}
+				assert.Empty(dst.issueNotes)
+			},
+		},
+		{
+			"Award emoji without user tokens are summarized",
+			func(src, dst, alice *fakeClient) {
+				src.awardEmoji = makeAwardEmoji("thumbsup:alice", "thumbsup:bob", "thumbsup:carol", "heart:bob")
+				src.noteAwardEmoji = map[int][]*glab.AwardEmoji{1: makeAwardEmoji("tada:bob")}
+			},
+			func(err error, src, dst, alice *fakeClient) {
+				assert.NoError(err)
+				assert.Len(alice.awardEmoji, 1)
+				if assert.Len(dst.issueNotes, 1) {
+					body := dst.issueNotes[0].Body
+					assert.Contains(body, "- :thumbsup: 2 (@bob, @carol)\n")
+					assert.Contains(body, "- :heart: 1 (@bob)\n")
+					assert.Contains(body, "- :tada: 1 (@bob) on the comment of @me from ")
+				}
+			},
+		},
+		{
+			"Listing award emoji fails",
+			func(src, dst, alice *fakeClient) {
+				src.errors.listAwardEmoji = errors.New("err")
+			},
+			func(err error, src, dst, alice *fakeClient) {
+				assert.Error(err)
+			},
+		},
+		{
+			"Awarding emoji fails",
+			func(src, dst, alice *fakeClient) {
+				src.awardEmoji = makeAwardEmoji("thumbsup:alice")
+				alice.errors.createAwardEmoji = errors.New("err")
+			},
+			func(err error, src, dst, alice *fakeClient) {
+				assert.Error(err)
+			},
+		},
+	}
+	for _, run := range runs {
+		t.Run(run.name, func(t *testing.T) {
+			conf, err := config.Parse(strings.NewReader(cfg2))
+			require.NoError(err)
+			m, err := New(conf)
+			require.NoError(err)
+			_, err = m.SourceProject(m.params.SrcPrj.Name)
+			require.NoError(err)
+			_, err = m.DestProject(m.params.DstPrj.Name)
+			require.NoError(err)
+			alice := new(fakeClient)
+			m.toUsers["alice"] = alice
+			run.setup(source(m), dest(m), alice)
+			ds := makeDiscussions(makeNotes("n1")...)
+			ds[0].Notes[0].ID = 1
+			err = m.copyIssueAwardEmoji(0, 0, ds, map[int]int{1: 10})
+			run.asserts(err, source(m), dest(m), alice)
+		})
+	}
+}
+
+// makeAwardEmoji returns award emoji from "name:username" items.
+func makeAwardEmoji(items ...string) []*glab.AwardEmoji {
+	awards := make([]*glab.AwardEmoji, len(items))
+	for k, item := range items {
+		parts := strings.SplitN(item, ":", 2)
+		awards[k] = &glab.AwardEmoji{ID: k, Name: parts[0]}
+		awards[k].User.Username = parts[1]
+	}
+	return awards
+}
@@ -0,0 +1,128 @@
+package migration
+
+import (
+	"fmt"
+
+	"github.com/gotsunami/gitlab-copy/gitlab"
+	glab "github.com/xanzy/go-gitlab"
+)
+
+// listEnvironments returns all environments of project pid, fetching all
+// pages.
+func listEnvironments(c gitlab.GitLaber, pid int) ([]*glab.Environment, error) {
+	all := make([]*glab.Environment, 0)
+	opts := &glab.ListEnvironmentsOptions{ListOptions: glab.ListOptions{PerPage: ResultsPerPage, Page: 1}}
+	for {
+		es, _, err := c.ListEnvironments(pid, opts)
+		if err != nil {
+			return nil, err
+		}
+		if len(es) == 0 {
+			break
+		}
+		all = append(all, es...)
+		opts.Page++
+	}
+	return all, nil
+}
+
+// listFreezePeriods returns all deploy freeze periods of project pid,
+// fetching all pages.
+func listFreezePeriods(c gitlab.GitLaber, pid int) ([]*glab.FreezePeriod, error) {
+	all := make([]*glab.FreezePeriod, 0)
+	opts := &glab.ListFreezePeriodsOptions{PerPage: ResultsPerPage, Page: 1}
+	for {
+		fps, _, err := c.ListFreezePeriods(pid, opts)
+		if err != nil {
+			return nil, err
+		}
+		if len(fps) == 0 {
+			break
+		}
+		all = append(all, fps...)
+		opts.Page++
+	}
+	return all, nil
+}
+
+// freezePeriodKey identifies a freeze period by its schedule.
+func freezePeriodKey(fp *glab.FreezePeriod) string {
+	return fmt.Sprintf("%s|%s|%s", fp.FreezeStart, fp.FreezeEnd, fp.CronTimezone)
+}
+
+// migrateEnvironments copies the source environments which don't exist on
+// target yet (by name), with their external URL and tier, then the deploy
+// freeze periods which don't exist on target yet (by schedule).
+func (m *Migration) migrateEnvironments() error {
+	source := m.Endpoint.SrcClient
+	target := m.Endpoint.DstClient
+
+	srcProjectID := m.srcProject.ID
+	tarProjectID := m.dstProject.ID
+
+	fmt.Println("Copying environments ...")
+	envs, err := listEnvironments(source, srcProjectID)
+	if err != nil {
+		return fmt.Errorf("source: can't fetch environments: %s", err.Error())
+	}
+	fmt.Printf("Found %d environments\n", len(envs))
+	tes, err := listEnvironments(target, tarProjectID)
+	if err != nil {
+		return fmt.Errorf("target: can't fetch environments: %s", err.Error())
+	}
+	existing := make(map[string]bool)
+	for _, e := range tes {
+		existing[e.Name] = true
+	}
+	for _, e := range envs {
+		if existing[e.Name] {
+			fmt.Printf("target: environment %s already exists, skipping...\n", e.Name)
+			continue
+		}
+		eopts := &glab.CreateEnvironmentOptions{Name: &e.Name}
+		if e.ExternalURL != "" {
+			eopts.ExternalURL = &e.ExternalURL
+		}
+		if e.Tier != "" {
+			eopts.Tier = &e.Tier
+		}
+		if _, _, err := target.CreateEnvironment(tarProjectID, eopts); err != nil {
+			return fmt.Errorf("target: error creating environment '%s': %s", e.Name, err.Error())
+		}
+		fmt.Printf("target: created environment %s\n", e.Name)
+	}
+
+	fmt.Println("Copying deploy freeze periods ...")
+	fps, err := listFreezePeriods(source, srcProjectID)
+	if err != nil {
+		return fmt.Errorf("source: can't fetch freeze periods: %s", err.Error())
+	}
+	fmt.Printf("Found %d freeze periods\n", len(fps))
+	tfps, err := listFreezePeriods(target, tarProjectID)
+	if err != nil {
+		return fmt.Errorf("target: can't fetch freeze periods: %s", err.Error())
+	}
+	existing = make(map[string]bool)
+	for _, fp := range tfps {
+		existing[freezePeriodKey(fp)] = true
+	}
+	for _, fp := range fps {
+		what := fmt.Sprintf("%s to %s (%s)", fp.FreezeStart, fp.FreezeEnd, fp.CronTimezone)
+		if existing[freezePeriodKey(fp)] {
+			fmt.Printf("target: freeze period %s already exists, skipping...\n", what)
+			continue
+		}
+		fopts := &glab.CreateFreezePeriodOptions{
+			FreezeStart: &fp.FreezeStart,
+			FreezeEnd:   &fp.FreezeEnd,
+		}
+		if fp.CronTimezone != "" {
+			fopts.CronTimezone = &fp.CronTimezone
+		}
+		if _, _, err := target.CreateFreezePeriod(tarProjectID, fopts); err != nil {
+			return fmt.Errorf("target: error creating freeze period %s: %s", what, err.Error())
+		}
+		fmt.Printf("target: created freeze period %s\n", what)
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package migration
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/gotsunami/gitlab-copy/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	glab "github.com/xanzy/go-gitlab"
+)
+
+func TestMigrateEnvironments(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	runs := []struct {
+		name    string                     // Sub-test name
+		config  string                     // YAML config
+		setup   func(src, dst *fakeClient) // Defines any option before calling Migrate()
+		asserts func(err error, src, dst *fakeClient)
+	}{
+		{
+			"Listing source environments fails",
+			cfg18,
+			func(src, dst *fakeClient) {
+				src.errors.listEnvironments = errors.New("err")
+			},
+			func(err error, src, dst *fakeClient) {
+				assert.Error(err)
+			},
+		},
+		{
+			"Creating freeze period fails",
+			cfg18,
+			func(src, dst *fakeClient) {
+				src.freezePeriods = []*glab.FreezePeriod{{FreezeStart: "0 23 * * 5", FreezeEnd: "0 7 * * 1"}}
+				dst.errors.createFreezePeriod = errors.New("err")
+			},
+			func(err error, src, dst *fakeClient) {
+				assert.Error(err)
+			},
+		},
+		{
+			"Environments copied with their external URL and tier",
+			cfg18,
+			func(src, dst *fakeClient) {
+				src.environments = []*glab.Environment{
+					{Name: "production", ExternalURL: "https://example.com", Tier: "production"},
+					{Name: "staging", Tier: "staging"},
+				}
+				dst.environments = []*glab.Environment{{Name: "staging"}}
+			},
+			func(err error, src, dst *fakeClient) {
+				require.NoError(err)
+				if assert.Len(dst.environments, 2) {
+					e := dst.environments[1]
+					assert.Equal("production", e.Name)
+					assert.Equal("https://example.com", e.ExternalURL)
+					assert.Equal("production", e.Tier)
+				}
+			},
+		},
+		{
+			"Freeze periods copied unless existing with the same schedule",
+			cfg18,
+			func(src, dst *fakeClient) {
+				src.freezePeriods = []*glab.FreezePeriod{
+					{FreezeStart: "0 23 * * 5", FreezeEnd: "0 7 * * 1", CronTimezone: "UTC"},
+					{FreezeStart: "0 0 24 12 *", FreezeEnd: "0 0 2 1 *", CronTimezone: "Europe/Paris"},
+				}
+				dst.freezePeriods = []*glab.FreezePeriod{
+					{FreezeStart: "0 23 * * 5", FreezeEnd: "0 7 * * 1", CronTimezone: "UTC"},
+				}
+			},
+			func(err error, src, dst *fakeClient) {
+				require.NoError(err)
+				if assert.Len(dst.freezePeriods, 2) {
+					fp := dst.freezePeriods[1]
+					assert.Equal("0 0 24 12 *", fp.FreezeStart)
+					assert.Equal("0 0 2 1 *", fp.FreezeEnd)
+					assert.Equal("Europe/Paris", fp.CronTimezone)
+				}
+			},
+		},
+	}
+	for _, run := range runs {
+		t.Run(run.name, func(t *testing.T) {
+			conf, err := config.Parse(strings.NewReader(run.config))
+			require.NoError(err)
+			// Load the conf.
+			m, err := New(conf)
+			require.NoError(err)
+			_, err = m.SourceProject(m.params.SrcPrj.Name)
+			require.NoError(err)
+			_, err = m.DestProject(m.params.DstPrj.Name)
+			require.NoError(err)
+			// Setup.
+			run.setup(source(m), dest(m))
+			// Run the migration.
+			err = m.Migrate()
+			// Asserts and tear down.
+			run.asserts(err, source(m), dest(m))
+		})
+	}
+}
@@ -0,0 +1,227 @@
+package migration
+
+import (
+	"fmt"
+	"net/http"
+
+	glab "github.com/xanzy/go-gitlab"
+)
+
+// listEpics returns all epics of the group gid itself, oldest first.
+func listEpics(list func(*glab.ListGroupEpicsOptions) ([]*glab.Epic, error)) ([]*glab.Epic, error) {
+	all := make([]*glab.Epic, 0)
+	orderBy, sort, descendants := "created_at", "asc", false
+	opts := &glab.ListGroupEpicsOptions{
+		ListOptions:             glab.ListOptions{PerPage: ResultsPerPage, Page: 1},
+		OrderBy:                 &orderBy,
+		Sort:                    &sort,
+		IncludeDescendantGroups: &descendants,
+	}
+	for {
+		es, err := list(opts)
+		if err != nil {
+			return nil, err
+		}
+		if len(es) == 0 {
+			break
+		}
+		all = append(all, es...)
+		opts.Page++
+	}
+	return all, nil
+}
+
+// copyGroupLabels creates the source group labels named in names in the
+// target group, if not existing yet.
+func (m *Migration) copyGroupLabels(names map[string]bool) error {
+	if len(names) == 0 {
+		return nil
+	}
+	labels := make([]*glab.GroupLabel, 0)
+	opts := &glab.ListGroupLabelsOptions{ListOptions: glab.ListOptions{PerPage: ResultsPerPage, Page: 1}}
+	for {
+		ls, _, err := m.Endpoint.SrcClient.ListGroupLabels(m.params.SrcPrj.Group, opts)
+		if err != nil {
+			return fmt.Errorf("source: can't fetch group labels: %s", err.Error())
+		}
+		if len(ls) == 0 {
+			break
+		}
+		labels = append(labels, ls...)
+		opts.Page++
+	}
+	for _, label := range labels {
+		if !names[label.Name] {
+			continue
+		}
+		if err := m.createGroupLabel(label.Name, label.Color, label.Description); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// migrateEpic creates the source epic e in the target group, along with its
+// notes, and returns the new epic.
+func (m *Migration) migrateEpic(e *glab.Epic) (*glab.Epic, error) {
+	target := m.Endpoint.DstClient
+	gid := m.params.DstPrj.Group
+
+	labels := glab.Labels(e.Labels)
+	eopts := &glab.CreateEpicOptions{
+		Title:       &e.Title,
+		Description: &e.Description,
+		Labels:      &labels,
+	}
+	if e.StartDateIsFixed {
+		eopts.StartDateIsFixed = &e.StartDateIsFixed
+		eopts.StartDateFixed = e.StartDateFixed
+	}
+	if e.DueDateIsFixed {
+		eopts.DueDateIsFixed = &e.DueDateIsFixed
+		eopts.DueDateFixed = e.DueDateFixed
+	}
+	ne, _, err := target.CreateEpic(gid, eopts)
+	if err != nil {
+		return nil, fmt.Errorf("target: error creating epic '%s': %s", e.Title, err.Error())
+	}
+
+	ds, err := m.epicDiscussions(e.ID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := m.copyDiscussions(&epicThreads{gid: gid, id: ne.ID}, ds); err != nil {
+		return nil, err
+	}
+
+	if e.State == "closed" {
+		event := "close"
+		if _, _, err := target.UpdateEpic(gid, ne.IID, &glab.UpdateEpicOptions{StateEvent: &event}); err != nil {
+			return nil, fmt.Errorf("target: error closing epic &%d: %s", ne.IID, err.Error())
+		}
+	}
+	fmt.Printf("target: created epic &%d: %s [%s]\n", ne.IID, ne.Title, e.State)
+	return ne, nil
+}
+
+// attachEpicIssues assigns the copied issues of the source epic e to the
+// target epic te.
+func (m *Migration) attachEpicIssues(e, te *glab.Epic) error {
+	source := m.Endpoint.SrcClient
+	target := m.Endpoint.DstClient
+
+	issues := make([]*glab.Issue, 0)
+	opts := &glab.ListOptions{PerPage: ResultsPerPage, Page: 1}
+	for {
+		is, _, err := source.ListEpicIssues(m.params.SrcPrj.Group, e.IID, opts)
+		if err != nil {
+			return fmt.Errorf("source: can't get epic &%d issues: %s", e.IID, err.Error())
+		}
+		if len(is) == 0 {
+			break
+		}
+		issues = append(issues, is...)
+		opts.Page++
+	}
+	for _, issue := range issues {
+		if issue.ProjectID != m.srcProject.ID {
+			continue
+		}
+		tiid, ok := m.issues[issue.IID]
+		if !ok {
+			continue
+		}
+		ti, _, err := target.GetIssue(m.dstProject.ID, tiid)
+		if err != nil {
+			return fmt.Errorf("target: can't fetch issue #%d: %s", tiid, err.Error())
+		}
+		_, resp, err := target.AssignEpicIssue(m.params.DstPrj.Group, te.IID, ti.ID)
+		if err != nil {
+			// GitLab returns a 409 code if the issue is already assigned
+			if resp != nil && resp.StatusCode == http.StatusConflict {
+				continue
+			}
+			return fmt.Errorf("target: error assigning issue #%d to epic &%d: %s", tiid, te.IID, err.Error())
+		}
+	}
+	return nil
+}
+
+// migrateEpics copies the epics of the source group to the target group,
+// keeping their hierarchy, labels and notes. Epics already existing on target
+// (by title) are not copied again. The copied issues are then assigned to
+// their epics.
+func (m *Migration) migrateEpics() error {
+	source := m.Endpoint.SrcClient
+	target := m.Endpoint.DstClient
+
+	fmt.Println("Copying epics ...")
+	epics, err := listEpics(func(opts *glab.ListGroupEpicsOptions) ([]*glab.Epic, error) {
+		es, _, err := source.ListGroupEpics(m.params.SrcPrj.Group, opts)
+		return es, err
+	})
+	if err != nil {
+		return fmt.Errorf("source: can't fetch epics: %s", err.Error())
+	}
+	fmt.Printf("Found %d epics\n", len(epics))
+	tes, err := listEpics(func(opts *glab.ListGroupEpicsOptions) ([]*glab.Epic, error) {
+		es, _, err := target.ListGroupEpics(m.params.DstPrj.Group, opts)
+		return es, err
+	})
+	if err != nil {
+		return fmt.Errorf("target: can't fetch epics: %s", err.Error())
+	}
+	existing := make(map[string]*glab.Epic)
+	for _, te := range tes {
+		existing[te.Title] = te
+	}
+
+	names := make(map[string]bool)
+	for _, e := range epics {
+		for _, l := range e.Labels {
+			names[l] = true
+		}
+	}
+	if err := m.copyGroupLabels(names); err != nil {
+		return err
+	}
+
+	// Source epic IDs mapped to target epics.
+	copied := make(map[int]*glab.Epic)
+	created := make([]*glab.Epic, 0)
+	for _, e := range epics {
+		if te, ok := existing[e.Title]; ok {
+			fmt.Printf("target: epic &%d already exists, skipping...\n", e.IID)
+			copied[e.ID] = te
+			continue
+		}
+		te, err := m.migrateEpic(e)
+		if err != nil {
+			return err
+		}
+		copied[e.ID] = te
+		created = append(created, e)
+	}
+
+	// Hierarchy, once all epics exist on target.
+	for _, e := range created {
+		if e.ParentID == 0 {
+			continue
+		}
+		parent, ok := copied[e.ParentID]
+		if !ok {
+			continue
+		}
+		te := copied[e.ID]
+		if _, err := target.SetEpicParent(m.params.DstPrj.Group, te.IID, parent.ID); err != nil {
+			return fmt.Errorf("target: error setting parent of epic &%d: %s", te.IID, err.Error())
+		}
+	}
+
+	for _, e := range epics {
+		if err := m.attachEpicIssues(e, copied[e.ID]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
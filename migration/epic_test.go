@@ -0,0 +1,204 @@
+package migration
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gotsunami/gitlab-copy/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	glab "github.com/xanzy/go-gitlab"
+)
+
+func TestMigrateEpics(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	runs := []struct {
+		name    string
+		issues  map[int]int // Source IIDs mapped to target IIDs
+		setup   func(src, dst *fakeClient)
+		asserts func(err error, src, dst *fakeClient)
+	}{
+		{
+			"Listing source epics fails",
+			nil,
+			func(src, dst *fakeClient) {
+				src.errors.listEpics = errors.New("err")
+			},
+			func(err error, src, dst *fakeClient) {
+				assert.Error(err)
+			},
+		},
+		{
+			"Listing target epics fails",
+			nil,
+			func(src, dst *fakeClient) {
+				dst.errors.listEpics = errors.New("err")
+			},
+			func(err error, src, dst *fakeClient) {
+				assert.Error(err)
+			},
+		},
+		{
+			"Creating epic fails",
+			nil,
+			func(src, dst *fakeClient) {
+				src.epics = makeEpics("e1")
+				dst.errors.createEpic = errors.New("err")
+			},
+			func(err error, src, dst *fakeClient) {
+				assert.Error(err)
+			},
+		},
+		{
+			"Epics copied with labels, dates and state, existing ones skipped",
+			nil,
+			func(src, dst *fakeClient) {
+				src.epics = makeEpics("e1", "e2", "e3")
+				src.epics[1].Labels = []string{"team::a"}
+				src.epics[1].StartDateIsFixed = true
+				start := glab.ISOTime(time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC))
+				src.epics[1].StartDateFixed = &start
+				src.epics[2].State = "closed"
+				src.groupLabels = []*glab.GroupLabel{
+					{Name: "team::a", Color: "#ff0000"},
+					{Name: "unused", Color: "#00ff00"},
+				}
+				dst.epics = makeEpics("e1")
+			},
+			func(err error, src, dst *fakeClient) {
+				require.NoError(err)
+				if assert.Len(dst.epics, 3) {
+					assert.Equal([]string{"team::a"}, dst.epics[1].Labels)
+					assert.True(dst.epics[1].StartDateIsFixed)
+					assert.Equal(glab.ISOTime(time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC)), *dst.epics[1].StartDateFixed)
+					assert.Equal("opened", dst.epics[1].State)
+					assert.Equal("closed", dst.epics[2].State)
+				}
+				if assert.Len(dst.groupLabels, 1) {
+					assert.Equal("team::a", dst.groupLabels[0].Name)
+				}
+			},
+		},
+		{
+			"Hierarchy is kept",
+			nil,
+			func(src, dst *fakeClient) {
+				src.epics = makeEpics("parent", "child", "orphan")
+				src.epics[1].ParentID = src.epics[0].ID
+				src.epics[2].ParentID = 999
+			},
+			func(err error, src, dst *fakeClient) {
+				require.NoError(err)
+				if assert.Len(dst.epics, 3) {
+					assert.Zero(dst.epics[0].ParentID)
+					assert.Equal(dst.epics[0].ID, dst.epics[1].ParentID)
+					assert.Zero(dst.epics[2].ParentID)
+				}
+			},
+		},
+		{
+			"Setting parent fails",
+			nil,
+			func(src, dst *fakeClient) {
+				src.epics = makeEpics("parent", "child")
+				src.epics[1].ParentID = src.epics[0].ID
+				dst.errors.setEpicParent = errors.New("err")
+			},
+			func(err error, src, dst *fakeClient) {
+				assert.Error(err)
+			},
+		},
+		{
+			"Epic notes copied",
+			nil,
+			func(src, dst *fakeClient) {
+				src.epics = makeEpics("e1")
+				notes := makeNotes("n1")
+				notes[0].Body = "a note"
+				src.discussions = makeDiscussions(notes...)
+			},
+			func(err error, src, dst *fakeClient) {
+				require.NoError(err)
+				if assert.Len(dst.epicNotes, 1) {
+					assert.Contains(dst.epicNotes[0].Body, "a note")
+				}
+			},
+		},
+		{
+			"Copied issues reattached to their epics",
+			map[int]int{1: 0, 2: 1},
+			func(src, dst *fakeClient) {
+				src.epics = makeEpics("e1", "e2")
+				src.epicIssues = map[int][]*glab.Issue{
+					1: {{IID: 1}, {IID: 3}},
+					2: {{IID: 2}, {IID: 2, ProjectID: 42}},
+				}
+				dst.issues = makeIssues("i1", "i2")
+				dst.epics = makeEpics("e2")
+				dst.epicIssues = map[int][]*glab.Issue{1: {dst.issues[1]}}
+			},
+			func(err error, src, dst *fakeClient) {
+				require.NoError(err)
+				// e2 already exists on target as &1, e1 is created as &2.
+				if assert.Len(dst.epicIssues[2], 1) {
+					assert.Equal("i1", dst.epicIssues[2][0].Title)
+				}
+				if assert.Len(dst.epicIssues[1], 1) {
+					assert.Equal("i2", dst.epicIssues[1][0].Title)
+				}
+			},
+		},
+		{
+			"Assigning issue fails",
+			map[int]int{1: 0},
+			func(src, dst *fakeClient) {
+				src.epics = makeEpics("e1")
+				src.epicIssues = map[int][]*glab.Issue{1: {{IID: 1}}}
+				dst.issues = makeIssues("i1")
+				dst.errors.assignEpicIssue = errors.New("err")
+			},
+			func(err error, src, dst *fakeClient) {
+				assert.Error(err)
+			},
+		},
+	}
+	for _, run := range runs {
+		t.Run(run.name, func(t *testing.T) {
+			conf, err := config.Parse(strings.NewReader(cfg10))
+			require.NoError(err)
+			// Load the conf.
+			m, err := New(conf)
+			require.NoError(err)
+			_, err = m.SourceProject(m.params.SrcPrj.Name)
+			require.NoError(err)
+			_, err = m.DestProject(m.params.DstPrj.Name)
+			require.NoError(err)
+			// Setup.
+			for k, v := range run.issues {
+				m.issues[k] = v
+			}
+			run.setup(source(m), dest(m))
+			// Run the migration.
+			err = m.migrateEpics()
+			// Asserts and tear down.
+			run.asserts(err, source(m), dest(m))
+		})
+	}
+}
+
+func makeEpics(titles ...string) []*glab.Epic {
+	epics := make([]*glab.Epic, len(titles))
+	for k, t := range titles {
+		epics[k] = &glab.Epic{
+			ID:    100 + k,
+			IID:   k + 1,
+			Title: t,
+			State: "opened",
+		}
+	}
+	return epics
+}
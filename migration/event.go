@@ -0,0 +1,178 @@
+package migration
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	glab "github.com/xanzy/go-gitlab"
+)
+
+// timelineEntry is one line of an issue's history.
+type timelineEntry struct {
+	at   time.Time
+	text string
+}
+
+// byDate sorts timeline entries chronologically.
+type byDate []timelineEntry
+
+func (a byDate) Len() int           { return len(a) }
+func (a byDate) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+func (a byDate) Less(i, j int) bool { return a[i].at.Before(a[j].at) }
+
+// listTimeline returns the entries returned by list, fetching all pages.
+func listTimeline(list func(*glab.ListOptions) ([]timelineEntry, error)) ([]timelineEntry, error) {
+	all := make([]timelineEntry, 0)
+	opts := &glab.ListOptions{PerPage: ResultsPerPage, Page: 1}
+	for {
+		es, err := list(opts)
+		if err != nil {
+			return nil, err
+		}
+		if len(es) == 0 {
+			break
+		}
+		all = append(all, es...)
+		opts.Page++
+	}
+	return all, nil
+}
+
+// unavailable checks whether resp denotes an events API missing from the
+// GitLab tier or version, in which case there is simply no event.
+func unavailable(resp *glab.Response) bool {
+	return resp != nil && (resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusNotFound)
+}
+
+// newTimelineEntry returns the entry of an event done by user at t. user may
+// be nil for events whose author is unknown.
+func newTimelineEntry(t *time.Time, user *glab.BasicUser, format string, a ...interface{}) timelineEntry {
+	e := timelineEntry{text: fmt.Sprintf(format, a...)}
+	if t != nil {
+		e.at = *t
+	}
+	if user != nil {
+		e.text = fmt.Sprintf("@%s %s", user.Username, e.text)
+	}
+	return e
+}
+
+// issueTimeline returns the label, milestone, state and weight events of the
+// source issue iid, oldest first.
+func (m *Migration) issueTimeline(iid int) ([]timelineEntry, error) {
+	source := m.Endpoint.SrcClient
+	pid := m.srcProject.ID
+
+	labels, err := listTimeline(func(lo *glab.ListOptions) ([]timelineEntry, error) {
+		evs, _, err := source.ListIssueLabelEvents(pid, iid, &glab.ListLabelEventsOptions{ListOptions: *lo})
+		if err != nil {
+			return nil, err
+		}
+		es := make([]timelineEntry, len(evs))
+		for k, ev := range evs {
+			action := "added"
+			if ev.Action == "remove" {
+				action = "removed"
+			}
+			user := &glab.BasicUser{Username: ev.User.Username}
+			if ev.User.Username == "" {
+				user = nil
+			}
+			es[k] = newTimelineEntry(ev.CreatedAt, user, "%s label ~\"%s\"", action, ev.Label.Name)
+		}
+		return es, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("source: can't get issue #%d label events: %s", iid, err.Error())
+	}
+	milestones, err := listTimeline(func(lo *glab.ListOptions) ([]timelineEntry, error) {
+		evs, resp, err := source.ListIssueMilestoneEvents(pid, iid, &glab.ListMilestoneEventsOptions{ListOptions: *lo})
+		if err != nil {
+			if unavailable(resp) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		es := make([]timelineEntry, len(evs))
+		for k, ev := range evs {
+			action := "set"
+			if ev.Action == "remove" {
+				action = "removed"
+			}
+			title := ""
+			if ev.Milestone != nil {
+				title = ev.Milestone.Title
+			}
+			es[k] = newTimelineEntry(ev.CreatedAt, ev.User, "%s milestone %%\"%s\"", action, title)
+		}
+		return es, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("source: can't get issue #%d milestone events: %s", iid, err.Error())
+	}
+	states, err := listTimeline(func(lo *glab.ListOptions) ([]timelineEntry, error) {
+		evs, _, err := source.ListIssueStateEvents(pid, iid, &glab.ListStateEventsOptions{ListOptions: *lo})
+		if err != nil {
+			return nil, err
+		}
+		es := make([]timelineEntry, len(evs))
+		for k, ev := range evs {
+			es[k] = newTimelineEntry(ev.CreatedAt, ev.User, "%s the issue", ev.State)
+		}
+		return es, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("source: can't get issue #%d state events: %s", iid, err.Error())
+	}
+	weights, err := listTimeline(func(lo *glab.ListOptions) ([]timelineEntry, error) {
+		evs, resp, err := source.ListIssueWeightEvents(pid, iid, &glab.ListWeightEventsOptions{ListOptions: *lo})
+		if err != nil {
+			// Weight events come with paid tiers only.
+			if unavailable(resp) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		es := make([]timelineEntry, len(evs))
+		for k, ev := range evs {
+			es[k] = newTimelineEntry(ev.CreatedAt, ev.User, "changed weight to %d", ev.Weight)
+		}
+		return es, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("source: can't get issue #%d weight events: %s", iid, err.Error())
+	}
+
+	all := labels
+	all = append(all, milestones...)
+	all = append(all, states...)
+	all = append(all, weights...)
+	sort.Stable(byDate(all))
+	return all, nil
+}
+
+// copyIssueTimeline writes the history of the source issue srcIID (label,
+// milestone, state and weight changes) as a single note in the target issue
+// dstIID, since resource events can't be created through the API.
+func (m *Migration) copyIssueTimeline(srcIID, dstIID int) error {
+	entries, err := m.issueTimeline(srcIID)
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+	lines := make([]string, len(entries))
+	for k, e := range entries {
+		lines[k] = fmt.Sprintf("- %s: %s", e.at.Format(time.RFC1123), e.text)
+	}
+	body := fmt.Sprintf("History of the original issue:\n\n%s", strings.Join(lines, "\n"))
+	opts := &glab.CreateIssueNoteOptions{Body: &body}
+	if _, _, err := m.Endpoint.DstClient.CreateIssueNote(m.dstProject.ID, dstIID, opts); err != nil {
+		return fmt.Errorf("target: error adding history note to issue #%d: %s", dstIID, err.Error())
+	}
+	return nil
+}
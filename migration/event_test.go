@@ -0,0 +1,115 @@
+package migration
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gotsunami/gitlab-copy/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	glab "github.com/xanzy/go-gitlab"
+)
+
+func TestCopyIssueTimeline(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	at := func(day int) *time.Time {
+		d := time.Date(2023, 1, day, 10, 0, 0, 0, time.UTC)
+		return &d
+	}
+	bob := &glab.BasicUser{Username: "bob"}
+
+	runs := []struct {
+		name    string
+		setup   func(src, dst *fakeClient)
+		asserts func(err error, src, dst *fakeClient)
+	}{
+		{
+			"No events",
+			func(src, dst *fakeClient) {},
+			func(err error, src, dst *fakeClient) {
+				assert.NoError(err)
+				assert.Empty(dst.issueNotes)
+			},
+		},
+		{
+			"Milestone and weight events unavailable on free tier",
+			func(src, dst *fakeClient) {
+				src.freeTier = true
+				src.stateEvents = []*glab.StateEvent{{State: "closed", User: bob, CreatedAt: at(2)}}
+			},
+			func(err error, src, dst *fakeClient) {
+				require.NoError(err)
+				if assert.Len(dst.issueNotes, 1) {
+					assert.Contains(dst.issueNotes[0].Body, "@bob closed the issue")
+				}
+			},
+		},
+		{
+			"Events written as one chronological note",
+			func(src, dst *fakeClient) {
+				le := &glab.LabelEvent{Action: "add", CreatedAt: at(1)}
+				le.User.Username = "alice"
+				le.Label.Name = "needs review"
+				src.labelEvents = []*glab.LabelEvent{le}
+				src.milestoneEvents = []*glab.MilestoneEvent{
+					{Action: "add", CreatedAt: at(3), User: bob, Milestone: &glab.Milestone{Title: "v1"}},
+				}
+				src.stateEvents = []*glab.StateEvent{
+					{State: glab.ClosedEventType, CreatedAt: at(4), User: bob},
+					{State: glab.ReopenedEventType, CreatedAt: at(5)},
+				}
+				src.weightEvents = []*glab.WeightEvent{{Weight: 3, CreatedAt: at(2), User: bob}}
+			},
+			func(err error, src, dst *fakeClient) {
+				assert.NoError(err)
+				if assert.Len(dst.issueNotes, 1) {
+					expected := "History of the original issue:\n\n" +
+						"- Sun, 01 Jan 2023 10:00:00 UTC: @alice added label ~\"needs review\"\n" +
+						"- Mon, 02 Jan 2023 10:00:00 UTC: @bob changed weight to 3\n" +
+						"- Tue, 03 Jan 2023 10:00:00 UTC: @bob set milestone %\"v1\"\n" +
+						"- Wed, 04 Jan 2023 10:00:00 UTC: @bob closed the issue\n" +
+						"- Thu, 05 Jan 2023 10:00:00 UTC: reopened the issue"
+					assert.Equal(expected, dst.issueNotes[0].Body)
+				}
+			},
+		},
+		{
+			"Listing state events fails",
+			func(src, dst *fakeClient) {
+				src.errors.listStateEvents = errors.New("err")
+			},
+			func(err error, src, dst *fakeClient) {
+				assert.Error(err)
+			},
+		},
+		{
+			"Writing the note fails",
+			func(src, dst *fakeClient) {
+				src.weightEvents = []*glab.WeightEvent{{Weight: 3, CreatedAt: at(2)}}
+				dst.errors.createIssueNote = errors.New("err")
+			},
+			func(err error, src, dst *fakeClient) {
+				assert.Error(err)
+			},
+		},
+	}
+	for _, run := range runs {
+		t.Run(run.name, func(t *testing.T) {
+			conf, err := config.Parse(strings.NewReader(cfg2))
+			require.NoError(err)
+			m, err := New(conf)
+			require.NoError(err)
+			_, err = m.SourceProject(m.params.SrcPrj.Name)
+			require.NoError(err)
+			_, err = m.DestProject(m.params.DstPrj.Name)
+			require.NoError(err)
+			run.setup(source(m), dest(m))
+			err = m.copyIssueTimeline(0, 0)
+			run.asserts(err, source(m), dest(m))
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package migration
+
+import (
+	"fmt"
+
+	"github.com/gotsunami/gitlab-copy/gitlab"
+	glab "github.com/xanzy/go-gitlab"
+)
+
+// legacyFeatureFlag is the version of the feature flags which can't be
+// created anymore.
+const legacyFeatureFlag = "legacy_flag"
+
+// listFeatureFlags returns all feature flags of project pid, fetching all
+// pages.
+func listFeatureFlags(c gitlab.GitLaber, pid int) ([]*glab.ProjectFeatureFlag, error) {
+	all := make([]*glab.ProjectFeatureFlag, 0)
+	opts := &glab.ListProjectFeatureFlagOptions{ListOptions: glab.ListOptions{PerPage: ResultsPerPage, Page: 1}}
+	for {
+		fs, _, err := c.ListProjectFeatureFlags(pid, opts)
+		if err != nil {
+			return nil, err
+		}
+		if len(fs) == 0 {
+			break
+		}
+		all = append(all, fs...)
+		opts.Page++
+	}
+	return all, nil
+}
+
+// featureFlagStrategies returns the options to create the strategies of
+// flag f, with their parameters and environment scopes.
+func featureFlagStrategies(f *glab.ProjectFeatureFlag) *[]*glab.FeatureFlagStrategyOptions {
+	ss := make([]*glab.FeatureFlagStrategyOptions, len(f.Strategies))
+	for k, st := range f.Strategies {
+		// IDs belong to the source flag.
+		scopes := make([]*glab.ProjectFeatureFlagScope, len(st.Scopes))
+		for j, sc := range st.Scopes {
+			scopes[j] = &glab.ProjectFeatureFlagScope{EnvironmentScope: sc.EnvironmentScope}
+		}
+		ss[k] = &glab.FeatureFlagStrategyOptions{
+			Name:       glab.String(st.Name),
+			Parameters: st.Parameters,
+			Scopes:     &scopes,
+		}
+	}
+	return &ss
+}
+
+// migrateFeatureFlags copies the source feature flags which don't exist on
+// target yet (by name), with their description, active state and strategies.
+func (m *Migration) migrateFeatureFlags() error {
+	source := m.Endpoint.SrcClient
+	target := m.Endpoint.DstClient
+
+	srcProjectID := m.srcProject.ID
+	tarProjectID := m.dstProject.ID
+
+	fmt.Println("Copying feature flags ...")
+	flags, err := listFeatureFlags(source, srcProjectID)
+	if err != nil {
+		return fmt.Errorf("source: can't fetch feature flags: %s", err.Error())
+	}
+	fmt.Printf("Found %d feature flags\n", len(flags))
+	tfs, err := listFeatureFlags(target, tarProjectID)
+	if err != nil {
+		return fmt.Errorf("target: can't fetch feature flags: %s", err.Error())
+	}
+	existing := make(map[string]bool)
+	for _, f := range tfs {
+		existing[f.Name] = true
+	}
+
+	for _, f := range flags {
+		if existing[f.Name] {
+			fmt.Printf("target: feature flag %s already exists, skipping...\n", f.Name)
+			continue
+		}
+		if f.Version == legacyFeatureFlag {
+			fmt.Printf("target: feature flag %s is a legacy flag, which can't be created, skipping...\n", f.Name)
+			continue
+		}
+		fopts := &glab.CreateProjectFeatureFlagOptions{
+			Name:        &f.Name,
+			Description: &f.Description,
+			Active:      &f.Active,
+			Strategies:  featureFlagStrategies(f),
+		}
+		if f.Version != "" {
+			fopts.Version = &f.Version
+		}
+		if _, _, err := target.CreateProjectFeatureFlag(tarProjectID, fopts); err != nil {
+			return fmt.Errorf("target: error creating feature flag '%s': %s", f.Name, err.Error())
+		}
+		state := "active"
+		if !f.Active {
+			state = "inactive"
+		}
+		fmt.Printf("target: created feature flag %s [%s]\n", f.Name, state)
+	}
+	return nil
+}
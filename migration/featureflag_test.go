@@ -0,0 +1,123 @@
+package migration
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/gotsunami/gitlab-copy/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	glab "github.com/xanzy/go-gitlab"
+)
+
+func TestMigrateFeatureFlags(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	runs := []struct {
+		name    string                     // Sub-test name
+		config  string                     // YAML config
+		setup   func(src, dst *fakeClient) // Defines any option before calling Migrate()
+		asserts func(err error, src, dst *fakeClient)
+	}{
+		{
+			"Listing source feature flags fails",
+			cfg17,
+			func(src, dst *fakeClient) {
+				src.errors.listFeatureFlags = errors.New("err")
+			},
+			func(err error, src, dst *fakeClient) {
+				assert.Error(err)
+			},
+		},
+		{
+			"Creating feature flag fails",
+			cfg17,
+			func(src, dst *fakeClient) {
+				src.featureFlags = []*glab.ProjectFeatureFlag{{Name: "dark_mode"}}
+				dst.errors.createFeatureFlag = errors.New("err")
+			},
+			func(err error, src, dst *fakeClient) {
+				assert.Error(err)
+			},
+		},
+		{
+			"Existing and legacy flags are skipped",
+			cfg17,
+			func(src, dst *fakeClient) {
+				src.featureFlags = []*glab.ProjectFeatureFlag{
+					{Name: "dark_mode"},
+					{Name: "old", Version: legacyFeatureFlag},
+					{Name: "beta", Version: "new_version_flag"},
+				}
+				dst.featureFlags = []*glab.ProjectFeatureFlag{{Name: "dark_mode"}}
+			},
+			func(err error, src, dst *fakeClient) {
+				require.NoError(err)
+				if assert.Len(dst.featureFlags, 2) {
+					assert.Equal("beta", dst.featureFlags[1].Name)
+				}
+			},
+		},
+		{
+			"Flags copied with their state and strategies",
+			cfg17,
+			func(src, dst *fakeClient) {
+				src.featureFlags = []*glab.ProjectFeatureFlag{
+					{
+						Name:        "beta",
+						Description: "Beta features",
+						Active:      false,
+						Version:     "new_version_flag",
+						Strategies: []*glab.ProjectFeatureFlagStrategy{
+							{
+								ID:         3,
+								Name:       "gradualRolloutUserId",
+								Parameters: &glab.ProjectFeatureFlagStrategyParameter{GroupID: "default", Percentage: "25"},
+								Scopes: []*glab.ProjectFeatureFlagScope{
+									{ID: 7, EnvironmentScope: "production"},
+									{ID: 8, EnvironmentScope: "review/*"},
+								},
+							},
+						},
+					},
+				}
+			},
+			func(err error, src, dst *fakeClient) {
+				require.NoError(err)
+				require.Len(dst.featureFlags, 1)
+				f := dst.featureFlags[0]
+				assert.Equal("Beta features", f.Description)
+				assert.False(f.Active)
+				assert.Equal("new_version_flag", f.Version)
+				require.Len(f.Strategies, 1)
+				assert.Equal("gradualRolloutUserId", f.Strategies[0].Name)
+				assert.Equal("25", f.Strategies[0].Parameters.Percentage)
+				assert.Equal([]*glab.ProjectFeatureFlagScope{
+					{EnvironmentScope: "production"},
+					{EnvironmentScope: "review/*"},
+				}, f.Strategies[0].Scopes)
+			},
+		},
+	}
+	for _, run := range runs {
+		t.Run(run.name, func(t *testing.T) {
+			conf, err := config.Parse(strings.NewReader(run.config))
+			require.NoError(err)
+			// Load the conf.
+			m, err := New(conf)
+			require.NoError(err)
+			_, err = m.SourceProject(m.params.SrcPrj.Name)
+			require.NoError(err)
+			_, err = m.DestProject(m.params.DstPrj.Name)
+			require.NoError(err)
+			// Setup.
+			run.setup(source(m), dest(m))
+			// Run the migration.
+			err = m.Migrate()
+			// Asserts and tear down.
+			run.asserts(err, source(m), dest(m))
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package migration
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/gotsunami/gitlab-copy/gitlab"
+	glab "github.com/xanzy/go-gitlab"
+)
+
+// listHooks returns all webhooks of project pid, fetching all pages.
+func listHooks(c gitlab.GitLaber, pid int) ([]*glab.ProjectHook, error) {
+	all := make([]*glab.ProjectHook, 0)
+	opts := &glab.ListProjectHooksOptions{PerPage: ResultsPerPage, Page: 1}
+	for {
+		hs, _, err := c.ListProjectHooks(pid, opts)
+		if err != nil {
+			return nil, err
+		}
+		if len(hs) == 0 {
+			break
+		}
+		all = append(all, hs...)
+		opts.Page++
+	}
+	return all, nil
+}
+
+// HookName returns the scheme and host of the webhook URL rawURL. Its path
+// and query are left out since they often hold a secret (chat services).
+func HookName(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return "(invalid URL)"
+	}
+	name := u.Scheme + "://" + u.Host
+	if u.Path != "" && u.Path != "/" || u.RawQuery != "" {
+		name += "/..."
+	}
+	return name
+}
+
+// migrateHooks copies the webhooks of the source project, with their event
+// toggles and SSL verification setting. Their URL host is rewritten according
+// to the hookHosts config entry. Hooks whose URL already exists on target are
+// skipped. Secret tokens can't be read from the API and are not copied.
+func (m *Migration) migrateHooks() error {
+	fmt.Println("Copying webhooks ...")
+	hooks, err := listHooks(m.Endpoint.SrcClient, m.srcProject.ID)
+	if err != nil {
+		return fmt.Errorf("source: can't fetch webhooks: %s", err.Error())
+	}
+	fmt.Printf("Found %d webhooks\n", len(hooks))
+	ths, err := listHooks(m.Endpoint.DstClient, m.dstProject.ID)
+	if err != nil {
+		return fmt.Errorf("target: can't fetch webhooks: %s", err.Error())
+	}
+	existing := make(map[string]bool)
+	for _, h := range ths {
+		existing[h.URL] = true
+	}
+
+	for _, h := range hooks {
+		u := m.params.SrcPrj.HookURL(h.URL)
+		if existing[u] {
+			fmt.Printf("target: webhook %s already exists, skipping...\n", HookName(u))
+			continue
+		}
+		h := h
+		hopts := &glab.AddProjectHookOptions{
+			URL:                      &u,
+			PushEvents:               &h.PushEvents,
+			PushEventsBranchFilter:   &h.PushEventsBranchFilter,
+			IssuesEvents:             &h.IssuesEvents,
+			ConfidentialIssuesEvents: &h.ConfidentialIssuesEvents,
+			MergeRequestsEvents:      &h.MergeRequestsEvents,
+			TagPushEvents:            &h.TagPushEvents,
+			NoteEvents:               &h.NoteEvents,
+			ConfidentialNoteEvents:   &h.ConfidentialNoteEvents,
+			JobEvents:                &h.JobEvents,
+			PipelineEvents:           &h.PipelineEvents,
+			WikiPageEvents:           &h.WikiPageEvents,
+			DeploymentEvents:         &h.DeploymentEvents,
+			ReleasesEvents:           &h.ReleasesEvents,
+			EnableSSLVerification:    &h.EnableSSLVerification,
+		}
+		if _, _, err := m.Endpoint.DstClient.AddProjectHook(m.dstProject.ID, hopts); err != nil {
+			return fmt.Errorf("target: error creating webhook %s: %s", HookName(u), err.Error())
+		}
+		existing[u] = true
+		fmt.Printf("target: created webhook %s\n", HookName(u))
+	}
+	return nil
+}
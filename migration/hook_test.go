@@ -0,0 +1,105 @@
+package migration
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/gotsunami/gitlab-copy/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	glab "github.com/xanzy/go-gitlab"
+)
+
+func TestMigrateHooks(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	runs := []struct {
+		name    string                     // Sub-test name
+		config  string                     // YAML config
+		setup   func(src, dst *fakeClient) // Defines any option before calling Migrate()
+		asserts func(err error, src, dst *fakeClient)
+	}{
+		{
+			"Listing source hooks fails",
+			cfg13,
+			func(src, dst *fakeClient) {
+				src.errors.listProjectHooks = errors.New("err")
+			},
+			func(err error, src, dst *fakeClient) {
+				assert.Error(err)
+			},
+		},
+		{
+			"Listing target hooks fails",
+			cfg13,
+			func(src, dst *fakeClient) {
+				dst.errors.listProjectHooks = errors.New("err")
+			},
+			func(err error, src, dst *fakeClient) {
+				assert.Error(err)
+			},
+		},
+		{
+			"Creating hook fails",
+			cfg13,
+			func(src, dst *fakeClient) {
+				src.hooks = []*glab.ProjectHook{{URL: "https://chat.com/hook"}}
+				dst.errors.addProjectHook = errors.New("err")
+			},
+			func(err error, src, dst *fakeClient) {
+				assert.Error(err)
+			},
+		},
+		{
+			"Hooks copied with their settings, host rewritten, existing ones skipped",
+			cfg13,
+			func(src, dst *fakeClient) {
+				src.hooks = []*glab.ProjectHook{
+					{URL: "https://ci.old.com/trigger", PushEvents: true, PushEventsBranchFilter: "main", EnableSSLVerification: true},
+					{URL: "https://chat.com/hook/secret", IssuesEvents: true, MergeRequestsEvents: true, NoteEvents: true},
+				}
+				dst.hooks = []*glab.ProjectHook{{URL: "https://chat.com/hook/secret"}}
+			},
+			func(err error, src, dst *fakeClient) {
+				require.NoError(err)
+				if assert.Len(dst.hooks, 2) {
+					h := dst.hooks[1]
+					assert.Equal("https://ci.new.com/trigger", h.URL)
+					assert.True(h.PushEvents)
+					assert.Equal("main", h.PushEventsBranchFilter)
+					assert.True(h.EnableSSLVerification)
+					assert.False(h.IssuesEvents)
+				}
+			},
+		},
+	}
+	for _, run := range runs {
+		t.Run(run.name, func(t *testing.T) {
+			conf, err := config.Parse(strings.NewReader(run.config))
+			require.NoError(err)
+			// Load the conf.
+			m, err := New(conf)
+			require.NoError(err)
+			_, err = m.SourceProject(m.params.SrcPrj.Name)
+			require.NoError(err)
+			_, err = m.DestProject(m.params.DstPrj.Name)
+			require.NoError(err)
+			// Setup.
+			run.setup(source(m), dest(m))
+			// Run the migration.
+			err = m.Migrate()
+			// Asserts and tear down.
+			run.asserts(err, source(m), dest(m))
+		})
+	}
+}
+
+func TestHookName(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("https://chat.com/...", HookName("https://chat.com/services/T0/B0/secret"))
+	assert.Equal("https://ci.com:8443", HookName("https://ci.com:8443/"))
+	assert.Equal("(invalid URL)", HookName("not a url"))
+}
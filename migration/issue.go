@@ -7,6 +7,7 @@ import (
 	"log"
 	"net/http"
 	"sort"
+	"strings"
 	"text/template"
 	"time"
 
@@ -37,6 +38,12 @@ type Migration struct {
 	srcProject, dstProject *glab.Project
 	toUsers                map[string]gitlab.GitLaber
 	skipIssue              bool
+	// Source upload paths mapped to their target counterparts
+	uploads map[string]string
+	// Source issue IIDs mapped to target issue IIDs
+	issues map[int]int
+	// Milestone titles mapped to target milestone IDs
+	milestones map[string]int
 }
 
 // New creates a new migration.
@@ -46,6 +53,9 @@ func New(c *config.Config) (*Migration, error) {
 	}
 	m := &Migration{params: c}
 	m.toUsers = make(map[string]gitlab.GitLaber)
+	m.uploads = make(map[string]string)
+	m.issues = make(map[int]int)
+	m.milestones = make(map[string]int)
 
 	fromgl, err := gitlab.Service().WithToken(
 		c.SrcPrj.Token,
@@ -105,6 +115,101 @@ func (m *Migration) DestProject(name string) (*glab.Project, error) {
 	return p, nil
 }
 
+// targetUserID returns the ID of the target user matching username, or nil if
+// no such user exists on target. User may have a different ID on target.
+func (m *Migration) targetUserID(username string) (*int, error) {
+	opts := &glab.ListUsersOptions{Username: &username}
+	users, _, err := m.Endpoint.DstClient.ListUsers(opts)
+	if err != nil {
+		return nil, fmt.Errorf("target: error fetching users: %v", err)
+	}
+	for _, u := range users {
+		if u.Username == username {
+			id := u.ID
+			return &id, nil
+		}
+	}
+	return nil, nil
+}
+
+// targetAssigneeIDs returns the target IDs of the users matching usernames,
+// or nil if none matches. Users without a match on target are listed in the
+// output, what describing the assigned source item.
+func (m *Migration) targetAssigneeIDs(usernames []string, what string) (*[]int, error) {
+	ids := make([]int, 0)
+	unmatched := make([]string, 0)
+	for _, username := range usernames {
+		uid, err := m.targetUserID(username)
+		if err != nil {
+			return nil, err
+		}
+		if uid == nil {
+			unmatched = append(unmatched, "@"+username)
+			continue
+		}
+		ids = append(ids, *uid)
+	}
+	if len(unmatched) > 0 {
+		fmt.Printf("target: no user matching %s, not assigned to %s\n", strings.Join(unmatched, ", "), what)
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	return &ids, nil
+}
+
+// issueAssignees returns the usernames of the issue's assignees. The single
+// Assignee is used with GitLab versions lacking multiple assignees.
+func issueAssignees(issue *glab.Issue) []string {
+	names := make([]string, 0)
+	for _, a := range issue.Assignees {
+		if a != nil && a.Username != "" {
+			names = append(names, a.Username)
+		}
+	}
+	if len(names) == 0 && issue.Assignee != nil && issue.Assignee.Username != "" {
+		names = append(names, issue.Assignee.Username)
+	}
+	return names
+}
+
+// targetMilestoneID returns the ID of the target milestone with the same title
+// as mi. The milestone is created on target if not existing yet.
+func (m *Migration) targetMilestoneID(mi *glab.Milestone) (*int, error) {
+	if id, ok := m.milestones[mi.Title]; ok {
+		return &id, nil
+	}
+	miles, err := listMilestones(m.Endpoint.DstClient, m.dstProject.ID)
+	if err != nil {
+		return nil, fmt.Errorf("target: error listing milestones: %s", err.Error())
+	}
+	for _, tmi := range miles {
+		if tmi.Title == mi.Title {
+			id := tmi.ID
+			m.milestones[mi.Title] = id
+			return &id, nil
+		}
+	}
+	tmi, err := m.createMilestone(mi)
+	if err != nil {
+		return nil, err
+	}
+	return &tmi.ID, nil
+}
+
+// noteWriter returns the target client to use for writing note n, along with
+// the body to write, with links to uploads rewritten. If a token is available
+// for the note's author, the note is written with user ownership. Otherwise, a
+// header is added to the body to mention the original author.
+func (m *Migration) noteWriter(n *glab.Note) (gitlab.GitLaber, string) {
+	body := m.rewriteUploads(n.Body)
+	if uc, ok := m.toUsers[n.Author.Username]; ok {
+		return uc, body
+	}
+	head := fmt.Sprintf("%s @%s wrote on %s :", n.Author.Name, n.Author.Username, n.CreatedAt.Format(time.RFC1123))
+	return m.Endpoint.DstClient, fmt.Sprintf("%s\n\n%s", head, body)
+}
+
 func (m *Migration) migrateIssue(issueID int) error {
 	source := m.Endpoint.SrcClient
 	target := m.Endpoint.DstClient
@@ -112,7 +217,7 @@ func (m *Migration) migrateIssue(issueID int) error {
 	srcProjectID := m.srcProject.ID
 	tarProjectID := m.dstProject.ID
 
-	issue, _, err := source.GetIssue(srcProjectID, issueID)
+	issue, health, _, err := source.GetIssueWithHealthStatus(srcProjectID, issueID)
 	if err != nil {
 		return fmt.Errorf("target: can't fetch issue: %s", err.Error())
 	}
@@ -123,59 +228,35 @@ func (m *Migration) migrateIssue(issueID int) error {
 	for _, t := range tis {
 		if issue.Title == t.Title {
 			// Target issue already exists, let's skip this one.
+			m.issues[issue.IID] = t.IID
 			return errDuplicateIssue
 		}
 	}
 	labels := make(glab.Labels, 0)
+	desc := m.rewriteUploads(issue.Description)
 	iopts := &glab.CreateIssueOptions{
-		Title:       &issue.Title,
-		Description: &issue.Description,
-		Labels:      &labels,
-	}
-	if issue.Assignee != nil && issue.Assignee.Username != "" {
-		// Assigned, does target user exist?
-		// User may have a different ID on target
-		users, _, err := target.ListUsers(nil)
-		if err == nil {
-			for _, u := range users {
-				if u.Username == issue.Assignee.Username {
-					iopts.AssigneeIDs = &[]int{u.ID}
-					break
-				}
-			}
-		} else {
-			return fmt.Errorf("target: error fetching users: %v", err)
-		}
+		Title:        &issue.Title,
+		Description:  &desc,
+		Labels:       &labels,
+		Confidential: &issue.Confidential,
+		DueDate:      issue.DueDate,
+		IssueType:    issue.IssueType,
 	}
+	if issue.Weight > 0 {
+		iopts.Weight = &issue.Weight
+	}
+	// Assigned, do target users exist?
+	ids, err := m.targetAssigneeIDs(issueAssignees(issue), fmt.Sprintf("issue #%d", issue.IID))
+	if err != nil {
+		return err
+	}
+	iopts.AssigneeIDs = ids
 	if issue.Milestone != nil && issue.Milestone.Title != "" {
-		miles, _, err := target.ListMilestones(tarProjectID, nil)
-		if err == nil {
-			found := false
-			for _, mi := range miles {
-				found = false
-				if mi.Title == issue.Milestone.Title {
-					found = true
-					iopts.MilestoneID = &mi.ID
-					break
-				}
-			}
-			if !found {
-				// Create target milestone
-				cmopts := &glab.CreateMilestoneOptions{
-					Title:       &issue.Milestone.Title,
-					Description: &issue.Milestone.Description,
-					DueDate:     issue.Milestone.DueDate,
-				}
-				mi, _, err := target.CreateMilestone(tarProjectID, cmopts)
-				if err == nil {
-					iopts.MilestoneID = &mi.ID
-				} else {
-					return fmt.Errorf("target: error creating milestone '%s': %s", issue.Milestone.Title, err.Error())
-				}
-			}
-		} else {
-			return fmt.Errorf("target: error listing milestones: %s", err.Error())
+		mid, err := m.targetMilestoneID(issue.Milestone)
+		if err != nil {
+			return err
 		}
+		iopts.MilestoneID = mid
 	}
 	// Copy existing labels.
 	for _, label := range issue.Labels {
@@ -200,44 +281,32 @@ func (m *Migration) migrateIssue(issueID int) error {
 		}
 	}
 
-	// Copy related notes (comments)
-	notes, _, err := source.ListIssueNotes(srcProjectID, issue.IID, nil)
-	if err != nil {
-		return fmt.Errorf("source: can't get issue #%d notes: %s", issue.IID, err.Error())
-	}
-	opts := &glab.CreateIssueNoteOptions{}
-	// Notes on target will be added in reverse order.
-	for j := len(notes) - 1; j >= 0; j-- {
-		n := notes[j]
-		target = m.Endpoint.DstClient
-		// Can we write the comment with user ownership?
-		if _, ok := m.toUsers[n.Author.Username]; ok {
-			target = m.toUsers[n.Author.Username]
-			opts.Body = &n.Body
-		} else {
-			// Nope. Let's add a header note instead.
-			head := fmt.Sprintf("%s @%s wrote on %s :", n.Author.Name, n.Author.Username, n.CreatedAt.Format(time.RFC1123))
-			bd := fmt.Sprintf("%s\n\n%s", head, n.Body)
-			opts.Body = &bd
-		}
-		_, resp, err := target.CreateIssueNote(tarProjectID, ni.IID, opts)
-		if err != nil {
-			if resp.StatusCode == http.StatusRequestURITooLong {
-				fmt.Printf("target: note's body too long, shortening it ...\n")
-				if len(*opts.Body) > 1024 {
-					smallb := (*opts.Body)[:1024]
-					opts.Body = &smallb
-				}
-				_, _, err := target.CreateIssueNote(tarProjectID, ni.ID, opts)
-				if err != nil {
-					return fmt.Errorf("target: error creating note (with shorter body) for issue #%d: %s", ni.IID, err.Error())
-				}
-			} else {
-				return fmt.Errorf("target: error creating note for issue #%d: %s", ni.IID, err.Error())
-			}
+	m.issues[issue.IID] = ni.IID
+
+	if health != "" {
+		if _, err := target.SetIssueHealthStatus(tarProjectID, ni.IID, health); err != nil {
+			return fmt.Errorf("target: error setting health status of issue #%d: %s", ni.IID, err.Error())
 		}
 	}
-	target = m.Endpoint.DstClient
+
+	// Copy related notes (comments), keeping threads
+	ds, err := m.issueDiscussions(issue.IID)
+	if err != nil {
+		return err
+	}
+	notes, err := m.copyDiscussions(&issueThreads{pid: tarProjectID, iid: ni.IID}, ds)
+	if err != nil {
+		return err
+	}
+	if err := m.copyIssueAwardEmoji(issue.IID, ni.IID, ds, notes); err != nil {
+		return err
+	}
+	if err := m.copyTimeStats(issue, ni.IID, ds); err != nil {
+		return err
+	}
+	if err := m.copyIssueTimeline(issue.IID, ni.IID); err != nil {
+		return err
+	}
 
 	if issue.State == "closed" {
 		event := "close"
@@ -247,6 +316,15 @@ func (m *Migration) migrateIssue(issueID int) error {
 			return fmt.Errorf("target: error closing issue #%d: %s", ni.IID, err.Error())
 		}
 	}
+	// Lock discussion once all notes are copied
+	if issue.DiscussionLocked {
+		locked := true
+		_, _, err := target.UpdateIssue(tarProjectID, ni.IID,
+			&glab.UpdateIssueOptions{DiscussionLocked: &locked})
+		if err != nil {
+			return fmt.Errorf("target: error locking issue #%d: %s", ni.IID, err.Error())
+		}
+	}
 	// Add a link to target issue if needed
 	if m.params.SrcPrj.LinkToTargetIssue {
 		var dstProjectURL string
@@ -313,10 +391,21 @@ func (m *Migration) Migrate() error {
 	}
 
 	source := m.Endpoint.SrcClient
-	target := m.Endpoint.DstClient
-
 	srcProjectID := m.srcProject.ID
-	tarProjectID := m.dstProject.ID
+
+	// Settings first, since they may enable features used by the next steps.
+	if len(m.params.SrcPrj.Settings) > 0 {
+		if err := m.migrateSettings(); err != nil {
+			return err
+		}
+	}
+
+	// Members next, so that notes can keep their ownership.
+	if m.params.SrcPrj.Members {
+		if err := m.migrateMembers(); err != nil {
+			return err
+		}
+	}
 
 	curPage := 1
 	optSort := "asc"
@@ -325,19 +414,22 @@ func (m *Migration) Migrate() error {
 	s := make([]issueID, 0)
 
 	// Copy all source labels on target
-	labels, _, err := source.ListLabels(srcProjectID, nil)
-	if err != nil {
-		return fmt.Errorf("source: can't fetch labels: %s", err.Error())
+	if err := m.migrateLabels(); err != nil {
+		return err
 	}
-	fmt.Printf("Found %d labels ...\n", len(labels))
-	for _, label := range labels {
-		clopts := &glab.CreateLabelOptions{Name: &label.Name, Color: &label.Color, Description: &label.Description}
-		_, resp, err := target.CreateLabel(tarProjectID, clopts)
-		if err != nil {
-			// GitLab returns a 409 code if label already exists
-			if resp.StatusCode != http.StatusConflict {
-				return fmt.Errorf("target: error creating label '%s': %s", label, err.Error())
-			}
+
+	// Milestones, created or updated on target, are then used by boards and
+	// issues.
+	if !m.params.SrcPrj.LabelsOnly {
+		if err := m.migrateMilestones(); err != nil {
+			return err
+		}
+	}
+
+	// Boards, once their labels and milestones exist on target.
+	if m.params.SrcPrj.Boards {
+		if err := m.migrateBoards(); err != nil {
+			return err
 		}
 	}
 
@@ -347,34 +439,6 @@ func (m *Migration) Migrate() error {
 	}
 
 	if m.params.SrcPrj.MilestonesOnly {
-		fmt.Println("Copying milestones ...")
-		miles, _, err := source.ListMilestones(srcProjectID, nil)
-		if err != nil {
-			return fmt.Errorf("error getting the milestones from source project: %s", err.Error())
-		}
-		fmt.Printf("Found %d milestones\n", len(miles))
-		for _, mi := range miles {
-			// Create target milestone
-			cmopts := &glab.CreateMilestoneOptions{
-				Title:       &mi.Title,
-				Description: &mi.Description,
-				DueDate:     mi.DueDate,
-			}
-			tmi, _, err := target.CreateMilestone(tarProjectID, cmopts)
-			if err != nil {
-				return fmt.Errorf("target: error creating milestone '%s': %s", mi.Title, err.Error())
-			}
-			if mi.State == "closed" {
-				event := "close"
-				umopts := &glab.UpdateMilestoneOptions{
-					StateEvent: &event,
-				}
-				_, _, err := target.UpdateMilestone(tarProjectID, tmi.ID, umopts)
-				if err != nil {
-					return fmt.Errorf("target: error closing milestone '%s': %s", mi.Title, err.Error())
-				}
-			}
-		}
 		// We're done here
 		return nil
 	}
@@ -401,24 +465,111 @@ func (m *Migration) Migrate() error {
 	// Then sort
 	sort.Sort(byIID(s))
 
+	moved := make([]issueID, 0)
 	for _, issue := range s {
 		if m.params.SrcPrj.Matches(issue.IID) {
 			if err := m.migrateIssue(issue.IID); err != nil {
 				if err == errDuplicateIssue {
 					fmt.Printf("target: issue %d already exists, skipping...", issue.IID)
+					// Likely copied by a previous run which failed before
+					// deleting it.
+					moved = append(moved, issue)
 					continue
 				}
 				return err
 			}
-			if m.params.SrcPrj.MoveIssues {
-				// Delete issue from source project
-				_, err := source.DeleteIssue(srcProjectID, issue.ID)
-				if err != nil {
-					log.Printf("could not delete the issue %d: %s", issue.ID, err.Error())
-				}
+			moved = append(moved, issue)
+		}
+	}
+
+	// Links are read from source issues, before they get deleted.
+	if err := m.migrateIssueLinks(); err != nil {
+		return err
+	}
+
+	// Epics are read from the source group, before issues get deleted.
+	if m.params.SrcPrj.Group != "" && m.params.DstPrj.Group != "" {
+		if err := m.migrateEpics(); err != nil {
+			return err
+		}
+	}
+
+	if m.params.SrcPrj.MoveIssues {
+		for _, issue := range moved {
+			// Delete issue from source project
+			_, err := source.DeleteIssue(srcProjectID, issue.ID)
+			if err != nil {
+				log.Printf("could not delete the issue %d: %s", issue.ID, err.Error())
 			}
 		}
 	}
 
+	if m.params.SrcPrj.MergeRequests {
+		if err := m.migrateMergeRequests(); err != nil {
+			return err
+		}
+	}
+
+	if m.params.SrcPrj.Wiki {
+		if err := m.migrateWiki(); err != nil {
+			return err
+		}
+	}
+
+	if m.params.SrcPrj.Snippets {
+		if err := m.migrateSnippets(); err != nil {
+			return err
+		}
+	}
+
+	if m.params.SrcPrj.Releases {
+		if err := m.migrateReleases(); err != nil {
+			return err
+		}
+	}
+
+	if m.params.SrcPrj.Variables {
+		if err := m.migrateVariables(); err != nil {
+			return err
+		}
+	}
+
+	if m.params.SrcPrj.Environments {
+		if err := m.migrateEnvironments(); err != nil {
+			return err
+		}
+	}
+
+	if m.params.SrcPrj.FeatureFlags {
+		if err := m.migrateFeatureFlags(); err != nil {
+			return err
+		}
+	}
+
+	if m.params.SrcPrj.ProtectedRefs {
+		if err := m.migrateProtectedRefs(); err != nil {
+			return err
+		}
+	}
+
+	// Approval rules, once their protected branches exist on target.
+	if m.params.SrcPrj.ApprovalRules {
+		if err := m.migrateApprovalRules(); err != nil {
+			return err
+		}
+	}
+
+	if m.params.SrcPrj.Badges {
+		if err := m.migrateBadges(); err != nil {
+			return err
+		}
+	}
+
+	if m.params.SrcPrj.Hooks {
+		if err := m.migrateHooks(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
@@ -3,6 +3,7 @@ package migration
 import (
 	"bytes"
 	"errors"
+	"fmt"
 	"strings"
 	"testing"
 	"time"
@@ -153,7 +154,7 @@ func TestMigrate(t *testing.T) {
 			func(err error, src, dst *fakeClient) {
 				require.NoError(err)
 				if assert.Equal(1, len(dst.milestones)) {
-					assert.Equal("close", dst.milestones[0].State)
+					assert.Equal("closed", dst.milestones[0].State)
 				}
 			},
 		},
@@ -330,6 +331,47 @@ func TestMigrateIssue(t *testing.T) {
 				}
 			},
 		},
+		{
+			"Issue has several assignees, some with a target user match",
+			cfg2,
+			func(src, dst *fakeClient) {
+				src.issues = makeIssues("issue1")
+				src.issues[0].Assignee.Username = "mat"
+				src.issues[0].Assignees = []*glab.IssueAssignee{
+					{Username: "mat"}, {Username: "bob"}, {Username: "alice"},
+				}
+				dst.users = makeUsers("alice", "mat")
+			},
+			func(err error, src, dst *fakeClient) {
+				assert.NoError(err)
+				if assert.Len(dst.issues, 1) {
+					names := make([]string, 0)
+					for _, a := range dst.issues[0].Assignees {
+						names = append(names, a.Username)
+					}
+					assert.Equal([]string{"mat", "alice"}, names)
+				}
+			},
+		},
+		{
+			"Assignee matching a target user past the first page of users",
+			cfg2,
+			func(src, dst *fakeClient) {
+				src.issues = makeIssues("issue1")
+				src.issues[0].Assignee.Username = "zoe"
+				names := make([]string, defaultPerPage)
+				for k := range names {
+					names[k] = fmt.Sprintf("user%d", k)
+				}
+				dst.users = makeUsers(append(names, "zoe")...)
+			},
+			func(err error, src, dst *fakeClient) {
+				assert.NoError(err)
+				if assert.Len(dst.issues, 1) {
+					assert.Equal("zoe", dst.issues[0].Assignee.Username)
+				}
+			},
+		},
 		{
 			"Issue has a milestone, not target match",
 			cfg2,
@@ -347,6 +389,23 @@ func TestMigrateIssue(t *testing.T) {
 				}
 			},
 		},
+		{
+			"Issue has a milestone matching a target one past the first page",
+			cfg2,
+			func(src, dst *fakeClient) {
+				src.issues = makeIssues("issue1")
+				src.issues[0].Milestone = &glab.Milestone{Title: "v1.0"}
+				names := make([]string, defaultPerPage)
+				for k := range names {
+					names[k] = fmt.Sprintf("v0.%d", k)
+				}
+				dst.milestones = makeMilestones(append(names, "v1.0")...)
+			},
+			func(err error, src, dst *fakeClient) {
+				assert.NoError(err)
+				assert.Len(dst.milestones, defaultPerPage+1)
+			},
+		},
 		{
 			"Issue has a milestone, create target milestone error",
 			cfg2,
@@ -444,11 +503,11 @@ func TestMigrateIssue(t *testing.T) {
 			},
 		},
 		{
-			"List issue notes fails",
+			"List issue discussions fails",
 			cfg2,
 			func(src, dst *fakeClient) {
 				src.issues = makeIssues("issue1")
-				src.errors.listIssueNotes = errors.New("err")
+				src.errors.listDiscussions = errors.New("err")
 			},
 			func(err error, src, dst *fakeClient) {
 				assert.Error(err)
@@ -459,10 +518,51 @@ func TestMigrateIssue(t *testing.T) {
 			cfg2,
 			func(src, dst *fakeClient) {
 				src.issues = makeIssues("issue1")
-				src.issueNotes = makeNotes("n1", "n2")
+				src.discussions = makeDiscussions(makeNotes("n1", "n2")...)
+			},
+			func(err error, src, dst *fakeClient) {
+				assert.NoError(err)
+				assert.Len(dst.issueNotes, 2)
+				assert.Empty(dst.discussions)
+			},
+		},
+		{
+			"Issue has a thread with replies",
+			cfg2,
+			func(src, dst *fakeClient) {
+				src.issues = makeIssues("issue1")
+				src.discussions = []*glab.Discussion{makeThread(makeNotes("n1", "n2", "n3")...)}
 			},
 			func(err error, src, dst *fakeClient) {
 				assert.NoError(err)
+				assert.Empty(dst.issueNotes)
+				if assert.Len(dst.discussions, 1) {
+					assert.Len(dst.discussions[0].Notes, 3)
+				}
+			},
+		},
+		{
+			"Issue with a thread, create discussion error",
+			cfg2,
+			func(src, dst *fakeClient) {
+				src.issues = makeIssues("issue1")
+				src.discussions = []*glab.Discussion{makeThread(makeNotes("n1", "n2")...)}
+				dst.errors.createDiscussion = errors.New("err")
+			},
+			func(err error, src, dst *fakeClient) {
+				assert.Error(err)
+			},
+		},
+		{
+			"Issue with a thread, reply error",
+			cfg2,
+			func(src, dst *fakeClient) {
+				src.issues = makeIssues("issue1")
+				src.discussions = []*glab.Discussion{makeThread(makeNotes("n1", "n2")...)}
+				dst.errors.addDiscussionNote = errors.New("err")
+			},
+			func(err error, src, dst *fakeClient) {
+				assert.Error(err)
 			},
 		},
 		{
@@ -470,7 +570,7 @@ func TestMigrateIssue(t *testing.T) {
 			cfg2,
 			func(src, dst *fakeClient) {
 				src.issues = makeIssues("issue1")
-				src.issueNotes = makeNotes("n1", "n2")
+				src.discussions = makeDiscussions(makeNotes("n1", "n2")...)
 				dst.errors.createIssueNote = errors.New("err")
 			},
 			func(err error, src, dst *fakeClient) {
@@ -482,12 +582,13 @@ func TestMigrateIssue(t *testing.T) {
 			cfg2,
 			func(src, dst *fakeClient) {
 				src.issues = makeIssues("issue1")
-				src.issueNotes = makeNotes("n1", "n2")
+				notes := makeNotes("n1", "n2")
 				// Large data buffer to raise an URITooLong error.
 				buf := make([]byte, 1128)
 				desc := bytes.NewBuffer(buf)
 				desc.WriteString("Some desc")
-				src.issueNotes[1].Body = desc.String()
+				notes[1].Body = desc.String()
+				src.discussions = makeDiscussions(notes...)
 				dst.httpErrorRaiseURITooLong = true
 				dst.errors.createIssueNote = errors.New("err")
 			},
@@ -495,6 +596,92 @@ func TestMigrateIssue(t *testing.T) {
 				assert.Error(err)
 			},
 		},
+		{
+			"Issue and notes with attachments",
+			cfg2,
+			func(src, dst *fakeClient) {
+				src.issues = makeIssues("issue1")
+				src.issues[0].Description = "![shot](" + upload1 + ")"
+				notes := makeNotes("n1")
+				notes[0].Body = "[log](" + upload2 + ")"
+				src.discussions = makeDiscussions(notes...)
+				src.files = map[string][]byte{upload1: []byte("png"), upload2: []byte("log")}
+			},
+			func(err error, src, dst *fakeClient) {
+				assert.NoError(err)
+				assert.Len(dst.files, 2)
+				if assert.Len(dst.issues, 1) {
+					assert.Equal("![shot](/uploads/00000000000000000000000000000000/shot.png)", dst.issues[0].Description)
+				}
+				if assert.Len(dst.issueNotes, 1) {
+					assert.Contains(dst.issueNotes[0].Body, "[log](/uploads/00000000000000000000000000000001/log file.txt)")
+				}
+			},
+		},
+		{
+			"Issue metadata",
+			cfg2,
+			func(src, dst *fakeClient) {
+				src.issues = makeIssues("issue1")
+				due := glab.ISOTime(time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC))
+				incident := "incident"
+				src.issues[0].DueDate = &due
+				src.issues[0].Weight = 3
+				src.issues[0].Confidential = true
+				src.issues[0].DiscussionLocked = true
+				src.issues[0].IssueType = &incident
+				src.healthStatus = "at_risk"
+			},
+			func(err error, src, dst *fakeClient) {
+				assert.NoError(err)
+				if assert.Len(dst.issues, 1) {
+					i := dst.issues[0]
+					assert.Equal("2023-03-01", i.DueDate.String())
+					assert.Equal(3, i.Weight)
+					assert.True(i.Confidential)
+					assert.True(i.DiscussionLocked)
+					assert.Equal("incident", *i.IssueType)
+				}
+				assert.Equal("at_risk", dst.healthStatus)
+			},
+		},
+		{
+			"Issue without health status",
+			cfg2,
+			func(src, dst *fakeClient) {
+				src.issues = makeIssues("issue1")
+				// Would fail if called.
+				dst.errors.setIssueHealthStatus = errors.New("err")
+			},
+			func(err error, src, dst *fakeClient) {
+				assert.NoError(err)
+				assert.Len(dst.issues, 1)
+			},
+		},
+		{
+			"Setting health status fails",
+			cfg2,
+			func(src, dst *fakeClient) {
+				src.issues = makeIssues("issue1")
+				src.healthStatus = "on_track"
+				dst.errors.setIssueHealthStatus = errors.New("err")
+			},
+			func(err error, src, dst *fakeClient) {
+				assert.Error(err)
+			},
+		},
+		{
+			"Locked issue, with error when updating target issue",
+			cfg2,
+			func(src, dst *fakeClient) {
+				src.issues = makeIssues("issue1")
+				src.issues[0].DiscussionLocked = true
+				dst.errors.updateIssue = errors.New("err")
+			},
+			func(err error, src, dst *fakeClient) {
+				assert.Error(err)
+			},
+		},
 		{
 			"Closed issue",
 			cfg2,
@@ -540,6 +727,35 @@ func TestMigrateIssue(t *testing.T) {
 	}
 }
 
+func TestMoveIssuesAfterFailure(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	conf, err := config.Parse(strings.NewReader(cfg4))
+	require.NoError(err)
+	m, err := New(conf)
+	require.NoError(err)
+	src, dst := source(m), dest(m)
+	src.issues = makeIssues("issue1", "issue2")
+	for k, i := range src.issues {
+		i.ID, i.IID = 10+k, k
+	}
+	// Copying the second issue fails.
+	src.issues[1].DiscussionLocked = true
+	dst.errors.updateIssue = errors.New("err")
+
+	assert.Error(m.Migrate())
+	assert.Len(dst.issues, 2)
+	assert.Empty(src.deletedIssues)
+
+	// The first issue, already on target, is deleted on rerun.
+	dst.issues = dst.issues[:1]
+	dst.errors.updateIssue = nil
+	require.NoError(m.Migrate())
+	assert.Len(dst.issues, 2)
+	assert.Equal([]int{10, 11}, src.deletedIssues)
+}
+
 func makeLabels(names ...string) []*glab.Label {
 	labels := make([]*glab.Label, len(names))
 	for k, n := range names {
@@ -601,3 +817,25 @@ func makeNotes(names ...string) []*glab.Note {
 	}
 	return notes
 }
+
+// makeDiscussions returns one individual note discussion per note.
+func makeDiscussions(notes ...*glab.Note) []*glab.Discussion {
+	ds := make([]*glab.Discussion, len(notes))
+	for k, n := range notes {
+		ds[k] = &glab.Discussion{
+			ID:             fmt.Sprintf("%d", k),
+			IndividualNote: true,
+			Notes:          []*glab.Note{n},
+		}
+	}
+	return ds
+}
+
+// makeThread returns a discussion with notes, the first note starting the
+// thread.
+func makeThread(notes ...*glab.Note) *glab.Discussion {
+	return &glab.Discussion{
+		ID:    "thread",
+		Notes: notes,
+	}
+}
@@ -0,0 +1,134 @@
+package migration
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gotsunami/gitlab-copy/gitlab"
+	glab "github.com/xanzy/go-gitlab"
+)
+
+// listLabels returns all labels of project pid, including those inherited
+// from its ancestor groups, fetching all pages.
+func listLabels(c gitlab.GitLaber, pid int) ([]*glab.Label, error) {
+	all := make([]*glab.Label, 0)
+	opts := &glab.ListLabelsOptions{ListOptions: glab.ListOptions{PerPage: ResultsPerPage, Page: 1}}
+	for {
+		ls, _, err := c.ListLabels(pid, opts)
+		if err != nil {
+			return nil, err
+		}
+		if len(ls) == 0 {
+			break
+		}
+		all = append(all, ls...)
+		opts.Page++
+	}
+	return all, nil
+}
+
+// listLabelPriorities returns the priorities of the prioritized labels of
+// project pid, by label name, fetching all pages.
+func listLabelPriorities(c gitlab.GitLaber, pid int) (map[string]int, error) {
+	all := make(map[string]int)
+	opts := &glab.ListLabelsOptions{ListOptions: glab.ListOptions{PerPage: ResultsPerPage, Page: 1}}
+	for {
+		prios, resp, err := c.ListLabelPriorities(pid, opts)
+		if err != nil {
+			return nil, err
+		}
+		for name, p := range prios {
+			all[name] = p
+		}
+		// A page may hold no prioritized label, so rely on the next page
+		// header rather than on an empty result.
+		if resp == nil || resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return all, nil
+}
+
+// createGroupLabel creates a label in the target group, if not existing yet.
+func (m *Migration) createGroupLabel(name, color, description string) error {
+	clopts := &glab.CreateGroupLabelOptions{Name: &name, Color: &color, Description: &description}
+	_, resp, err := m.Endpoint.DstClient.CreateGroupLabel(m.params.DstPrj.Group, clopts)
+	if err != nil {
+		// GitLab returns a 409 code if label already exists
+		if resp == nil || resp.StatusCode != http.StatusConflict {
+			return fmt.Errorf("target: error creating group label '%s': %s", name, err.Error())
+		}
+	}
+	return nil
+}
+
+// migrateLabels copies the source labels on target, with their priority.
+// Labels inherited from a source group are mapped to the target labels of the
+// same name, if any. Otherwise they are created in the target group when one
+// is configured, as project labels if not.
+func (m *Migration) migrateLabels() error {
+	source := m.Endpoint.SrcClient
+	target := m.Endpoint.DstClient
+
+	srcProjectID := m.srcProject.ID
+	tarProjectID := m.dstProject.ID
+
+	labels, err := listLabels(source, srcProjectID)
+	if err != nil {
+		return fmt.Errorf("source: can't fetch labels: %s", err.Error())
+	}
+	fmt.Printf("Found %d labels ...\n", len(labels))
+	prios, err := listLabelPriorities(source, srcProjectID)
+	if err != nil {
+		return fmt.Errorf("source: can't fetch label priorities: %s", err.Error())
+	}
+
+	tls, err := listLabels(target, tarProjectID)
+	if err != nil {
+		return fmt.Errorf("target: can't fetch labels: %s", err.Error())
+	}
+	existing := make(map[string]bool)
+	for _, l := range tls {
+		existing[l.Name] = true
+	}
+	tprios, err := listLabelPriorities(target, tarProjectID)
+	if err != nil {
+		return fmt.Errorf("target: can't fetch label priorities: %s", err.Error())
+	}
+
+	for _, label := range labels {
+		prio, prioritized := prios[label.Name]
+		switch {
+		case existing[label.Name]:
+		case !label.IsProjectLabel && m.params.DstPrj.Group != "":
+			if err := m.createGroupLabel(label.Name, label.Color, label.Description); err != nil {
+				return err
+			}
+			fmt.Printf("target: created group label %s\n", label.Name)
+		default:
+			clopts := &glab.CreateLabelOptions{Name: &label.Name, Color: &label.Color, Description: &label.Description}
+			if prioritized {
+				clopts.Priority = &prio
+			}
+			_, resp, err := target.CreateLabel(tarProjectID, clopts)
+			if err != nil {
+				// GitLab returns a 409 code if label already exists
+				if resp == nil || resp.StatusCode != http.StatusConflict {
+					return fmt.Errorf("target: error creating label '%s': %s", label.Name, err.Error())
+				}
+			} else if prioritized {
+				tprios[label.Name] = prio
+			}
+		}
+		if tp, ok := tprios[label.Name]; !prioritized || ok && tp == prio {
+			continue
+		}
+		// Priorities are set per project, group labels included.
+		name := label.Name
+		if _, _, err := target.UpdateLabel(tarProjectID, &glab.UpdateLabelOptions{Name: &name, Priority: &prio}); err != nil {
+			return fmt.Errorf("target: error setting priority of label '%s': %s", label.Name, err.Error())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package migration
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/gotsunami/gitlab-copy/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	glab "github.com/xanzy/go-gitlab"
+)
+
+func TestMigrateLabels(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	runs := []struct {
+		name    string                     // Sub-test name
+		config  string                     // YAML config
+		setup   func(src, dst *fakeClient) // Defines any option before calling Migrate()
+		asserts func(err error, src, dst *fakeClient)
+	}{
+		{
+			"Listing source label priorities fails",
+			cfg1,
+			func(src, dst *fakeClient) {
+				src.errors.listLabelPriorities = errors.New("err")
+			},
+			func(err error, src, dst *fakeClient) {
+				assert.Error(err)
+			},
+		},
+		{
+			"Setting label priority fails",
+			cfg1,
+			func(src, dst *fakeClient) {
+				src.labels = []*glab.Label{{Name: "bug", IsProjectLabel: true}}
+				src.labelPriorities = map[string]int{"bug": 0}
+				dst.labels = []*glab.Label{{Name: "bug"}}
+				dst.errors.updateLabel = errors.New("err")
+			},
+			func(err error, src, dst *fakeClient) {
+				assert.Error(err)
+			},
+		},
+		{
+			"Project labels copied with their priority",
+			cfg1,
+			func(src, dst *fakeClient) {
+				src.labels = []*glab.Label{
+					{Name: "bug", IsProjectLabel: true},
+					{Name: "feature", IsProjectLabel: true},
+					{Name: "doc", IsProjectLabel: true},
+				}
+				src.labelPriorities = map[string]int{"bug": 0, "feature": 1}
+				dst.labels = []*glab.Label{{Name: "feature"}}
+			},
+			func(err error, src, dst *fakeClient) {
+				require.NoError(err)
+				assert.Len(dst.labels, 3)
+				assert.Equal(map[string]int{"bug": 0, "feature": 1}, dst.labelPriorities)
+			},
+		},
+		{
+			"Inherited labels created as project labels without target group",
+			cfg1,
+			func(src, dst *fakeClient) {
+				src.labels = []*glab.Label{{Name: "team"}}
+			},
+			func(err error, src, dst *fakeClient) {
+				require.NoError(err)
+				if assert.Len(dst.labels, 1) {
+					assert.Equal("team", dst.labels[0].Name)
+				}
+				assert.Empty(dst.groupLabels)
+			},
+		},
+		{
+			"Inherited labels mapped to target labels or created in target group",
+			cfg10,
+			func(src, dst *fakeClient) {
+				src.labels = []*glab.Label{
+					{Name: "team"},
+					{Name: "platform"},
+					{Name: "bug", IsProjectLabel: true},
+				}
+				src.labelPriorities = map[string]int{"team": 2}
+				dst.labels = []*glab.Label{{Name: "platform"}}
+			},
+			func(err error, src, dst *fakeClient) {
+				require.NoError(err)
+				if assert.Len(dst.groupLabels, 1) {
+					assert.Equal("team", dst.groupLabels[0].Name)
+				}
+				if assert.Len(dst.labels, 2) {
+					assert.Equal("bug", dst.labels[1].Name)
+				}
+				assert.Equal(map[string]int{"team": 2}, dst.labelPriorities)
+			},
+		},
+	}
+	for _, run := range runs {
+		t.Run(run.name, func(t *testing.T) {
+			conf, err := config.Parse(strings.NewReader(run.config))
+			require.NoError(err)
+			// Load the conf.
+			m, err := New(conf)
+			require.NoError(err)
+			_, err = m.SourceProject(m.params.SrcPrj.Name)
+			require.NoError(err)
+			_, err = m.DestProject(m.params.DstPrj.Name)
+			require.NoError(err)
+			// Setup.
+			run.setup(source(m), dest(m))
+			// Run the migration.
+			err = m.migrateLabels()
+			// Asserts and tear down.
+			run.asserts(err, source(m), dest(m))
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package migration
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+
+	glab "github.com/xanzy/go-gitlab"
+)
+
+// linkTypes maps the link types of the GitLab API to human readable text.
+var linkTypes = map[string]string{
+	"relates_to":    "relates to",
+	"blocks":        "blocks",
+	"is_blocked_by": "is blocked by",
+}
+
+// migrateIssueLinks recreates the links between source issues on their
+// target counterparts. Links pointing to issues that were not copied can't be
+// recreated: they are reported and written as a plain-text note instead.
+func (m *Migration) migrateIssueLinks() error {
+	source := m.Endpoint.SrcClient
+	target := m.Endpoint.DstClient
+
+	srcProjectID := m.srcProject.ID
+	tarProjectID := m.dstProject.ID
+	tarPid := strconv.Itoa(tarProjectID)
+
+	iids := make([]int, 0, len(m.issues))
+	for iid := range m.issues {
+		iids = append(iids, iid)
+	}
+	sort.Ints(iids)
+
+	// A link shows up on both its ends, create it once.
+	seen := make(map[int]bool)
+	for _, iid := range iids {
+		rels, _, err := source.ListIssueRelations(srcProjectID, iid)
+		if err != nil {
+			return fmt.Errorf("source: can't get issue #%d links: %s", iid, err.Error())
+		}
+		for _, rel := range rels {
+			if seen[rel.IssueLinkID] {
+				continue
+			}
+			seen[rel.IssueLinkID] = true
+			linkType := rel.LinkType
+			if linkType == "" {
+				linkType = "relates_to"
+			}
+			tiid, ok := m.issues[rel.IID]
+			if !ok || rel.ProjectID != srcProjectID {
+				if err := m.issueLinkNote(iid, rel, linkType); err != nil {
+					return err
+				}
+				continue
+			}
+			tiidStr := strconv.Itoa(tiid)
+			lopts := &glab.CreateIssueLinkOptions{
+				TargetProjectID: &tarPid,
+				TargetIssueIID:  &tiidStr,
+				LinkType:        &linkType,
+			}
+			_, resp, err := target.CreateIssueLink(tarProjectID, m.issues[iid], lopts)
+			if err != nil {
+				// GitLab returns a 409 code if the link already exists
+				if resp == nil || resp.StatusCode != http.StatusConflict {
+					return fmt.Errorf("target: error linking issue #%d to #%d: %s", m.issues[iid], tiid, err.Error())
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// issueLinkNote reports a link of the source issue iid that can't be
+// recreated on target, and writes it as a note of the target issue.
+func (m *Migration) issueLinkNote(iid int, rel *glab.IssueRelation, linkType string) error {
+	ref := rel.WebURL
+	if rel.References != nil && rel.References.Full != "" {
+		ref = rel.References.Full
+	}
+	if ref == "" {
+		ref = fmt.Sprintf("#%d", rel.IID)
+	}
+	human, ok := linkTypes[linkType]
+	if !ok {
+		human = linkType
+	}
+	tiid := m.issues[iid]
+	fmt.Printf("target: issue #%d %s %s, which was not copied: adding a note instead\n", tiid, human, ref)
+	body := fmt.Sprintf("This issue %s %s (%s).", human, ref, rel.Title)
+	opts := &glab.CreateIssueNoteOptions{Body: &body}
+	if _, _, err := m.Endpoint.DstClient.CreateIssueNote(m.dstProject.ID, tiid, opts); err != nil {
+		return fmt.Errorf("target: error adding link note to issue #%d: %s", tiid, err.Error())
+	}
+	return nil
+}
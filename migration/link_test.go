@@ -0,0 +1,120 @@
+package migration
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/gotsunami/gitlab-copy/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	glab "github.com/xanzy/go-gitlab"
+)
+
+func TestMigrateIssueLinks(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	runs := []struct {
+		name    string
+		issues  map[int]int // Source IIDs mapped to target IIDs
+		setup   func(src, dst *fakeClient)
+		asserts func(err error, src, dst *fakeClient)
+	}{
+		{
+			"No links",
+			map[int]int{1: 10, 2: 20},
+			func(src, dst *fakeClient) {},
+			func(err error, src, dst *fakeClient) {
+				assert.NoError(err)
+				assert.Empty(dst.issueLinks)
+			},
+		},
+		{
+			"Links between copied issues are created once",
+			map[int]int{1: 10, 2: 20, 3: 30},
+			func(src, dst *fakeClient) {
+				src.issueRelations = map[int][]*glab.IssueRelation{
+					1: {
+						{IID: 2, IssueLinkID: 100, LinkType: "blocks"},
+						{IID: 3, IssueLinkID: 101, LinkType: "relates_to"},
+					},
+					2: {{IID: 1, IssueLinkID: 100, LinkType: "is_blocked_by"}},
+					3: {{IID: 1, IssueLinkID: 101, LinkType: "relates_to"}},
+				}
+			},
+			func(err error, src, dst *fakeClient) {
+				require.NoError(err)
+				if assert.Len(dst.issueLinks, 2) {
+					assert.Equal(10, dst.issueLinks[0].SourceIssue.IID)
+					assert.Equal(20, dst.issueLinks[0].TargetIssue.IID)
+					assert.Equal("blocks", dst.issueLinks[0].LinkType)
+					assert.Equal(30, dst.issueLinks[1].TargetIssue.IID)
+					assert.Equal("relates_to", dst.issueLinks[1].LinkType)
+				}
+				assert.Empty(dst.issueNotes)
+			},
+		},
+		{
+			"Link to an issue not copied",
+			map[int]int{1: 10},
+			func(src, dst *fakeClient) {
+				src.issueRelations = map[int][]*glab.IssueRelation{
+					1: {{
+						IID:         7,
+						Title:       "Elsewhere",
+						IssueLinkID: 100,
+						LinkType:    "is_blocked_by",
+						References:  &glab.IssueReferences{Full: "other/project#7"},
+					}},
+				}
+			},
+			func(err error, src, dst *fakeClient) {
+				require.NoError(err)
+				assert.Empty(dst.issueLinks)
+				if assert.Len(dst.issueNotes, 1) {
+					assert.Equal("This issue is blocked by other/project#7 (Elsewhere).", dst.issueNotes[0].Body)
+				}
+			},
+		},
+		{
+			"Listing links fails",
+			map[int]int{1: 10},
+			func(src, dst *fakeClient) {
+				src.errors.listIssueRelations = errors.New("err")
+			},
+			func(err error, src, dst *fakeClient) {
+				assert.Error(err)
+			},
+		},
+		{
+			"Creating link fails",
+			map[int]int{1: 10, 2: 20},
+			func(src, dst *fakeClient) {
+				src.issueRelations = map[int][]*glab.IssueRelation{
+					1: {{IID: 2, IssueLinkID: 100, LinkType: "relates_to"}},
+				}
+				dst.errors.createIssueLink = errors.New("err")
+			},
+			func(err error, src, dst *fakeClient) {
+				assert.Error(err)
+			},
+		},
+	}
+	for _, run := range runs {
+		t.Run(run.name, func(t *testing.T) {
+			conf, err := config.Parse(strings.NewReader(cfg2))
+			require.NoError(err)
+			m, err := New(conf)
+			require.NoError(err)
+			_, err = m.SourceProject(m.params.SrcPrj.Name)
+			require.NoError(err)
+			_, err = m.DestProject(m.params.DstPrj.Name)
+			require.NoError(err)
+			m.issues = run.issues
+			run.setup(source(m), dest(m))
+			err = m.migrateIssueLinks()
+			run.asserts(err, source(m), dest(m))
+		})
+	}
+}
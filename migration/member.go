@@ -0,0 +1,72 @@
+package migration
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	glab "github.com/xanzy/go-gitlab"
+)
+
+// migrateMembers adds the direct members of the source project to the target
+// project, with the same access level and expiry date. Users are matched by
+// username; those without a match on target are reported.
+func (m *Migration) migrateMembers() error {
+	source := m.Endpoint.SrcClient
+	target := m.Endpoint.DstClient
+
+	srcProjectID := m.srcProject.ID
+	tarProjectID := m.dstProject.ID
+
+	fmt.Println("Copying members ...")
+
+	members := make([]*glab.ProjectMember, 0)
+	opts := &glab.ListProjectMembersOptions{ListOptions: glab.ListOptions{PerPage: ResultsPerPage, Page: 1}}
+	for {
+		ms, _, err := source.ListProjectMembers(srcProjectID, opts)
+		if err != nil {
+			return fmt.Errorf("source: can't fetch members: %s", err.Error())
+		}
+		if len(ms) == 0 {
+			break
+		}
+		members = append(members, ms...)
+		opts.Page++
+	}
+	fmt.Printf("Found %d members\n", len(members))
+
+	unmatched := make([]string, 0)
+	for _, mb := range members {
+		uid, err := m.targetUserID(mb.Username)
+		if err != nil {
+			return err
+		}
+		if uid == nil {
+			unmatched = append(unmatched, "@"+mb.Username)
+			continue
+		}
+		level := mb.AccessLevel
+		mopts := &glab.AddProjectMemberOptions{
+			UserID:      *uid,
+			AccessLevel: &level,
+		}
+		if mb.ExpiresAt != nil {
+			expires := mb.ExpiresAt.String()
+			mopts.ExpiresAt = &expires
+		}
+		_, resp, err := target.AddProjectMember(tarProjectID, mopts)
+		if err != nil {
+			// GitLab returns a 409 code if the user is already a member
+			if resp != nil && resp.StatusCode == http.StatusConflict {
+				fmt.Printf("target: @%s is already a member, skipping...\n", mb.Username)
+				continue
+			}
+			return fmt.Errorf("target: error adding member @%s: %s", mb.Username, err.Error())
+		}
+		fmt.Printf("target: added member @%s\n", mb.Username)
+	}
+	if len(unmatched) > 0 {
+		fmt.Printf("target: no user matching %s, not added as members\n", strings.Join(unmatched, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package migration
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gotsunami/gitlab-copy/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	glab "github.com/xanzy/go-gitlab"
+)
+
+func TestMigrateMembers(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	runs := []struct {
+		name    string                     // Sub-test name
+		config  string                     // YAML config
+		setup   func(src, dst *fakeClient) // Defines any option before calling Migrate()
+		asserts func(err error, src, dst *fakeClient)
+	}{
+		{
+			"Listing source members fails",
+			cfg9,
+			func(src, dst *fakeClient) {
+				src.errors.listProjectMembers = errors.New("err")
+			},
+			func(err error, src, dst *fakeClient) {
+				assert.Error(err)
+			},
+		},
+		{
+			"Fetching target users fails",
+			cfg9,
+			func(src, dst *fakeClient) {
+				src.members = makeMembers("alice")
+				dst.errors.listUsers = errors.New("err")
+			},
+			func(err error, src, dst *fakeClient) {
+				assert.Error(err)
+			},
+		},
+		{
+			"Adding member fails",
+			cfg9,
+			func(src, dst *fakeClient) {
+				src.members = makeMembers("alice")
+				dst.users = makeUsers("alice")
+				dst.errors.addProjectMember = errors.New("err")
+			},
+			func(err error, src, dst *fakeClient) {
+				assert.Error(err)
+			},
+		},
+		{
+			"Matched users added with access level and expiry date",
+			cfg9,
+			func(src, dst *fakeClient) {
+				src.members = makeMembers("alice", "bob", "carol")
+				src.members[0].AccessLevel = glab.MaintainerPermissions
+				expires := glab.ISOTime(time.Date(2030, 1, 2, 0, 0, 0, 0, time.UTC))
+				src.members[2].ExpiresAt = &expires
+				dst.users = makeUsers("alice", "carol")
+			},
+			func(err error, src, dst *fakeClient) {
+				require.NoError(err)
+				if assert.Len(dst.members, 2) {
+					assert.Equal("alice", dst.members[0].Username)
+					assert.Equal(glab.MaintainerPermissions, dst.members[0].AccessLevel)
+					assert.Nil(dst.members[0].ExpiresAt)
+					assert.Equal("carol", dst.members[1].Username)
+					assert.Equal(glab.DeveloperPermissions, dst.members[1].AccessLevel)
+					assert.Equal("2030-01-02", dst.members[1].ExpiresAt.String())
+				}
+			},
+		},
+		{
+			"Existing members are skipped",
+			cfg9,
+			func(src, dst *fakeClient) {
+				src.members = makeMembers("alice", "bob")
+				dst.users = makeUsers("alice", "bob")
+				dst.members = []*glab.ProjectMember{{ID: 0, Username: "alice", AccessLevel: glab.OwnerPermissions}}
+			},
+			func(err error, src, dst *fakeClient) {
+				require.NoError(err)
+				if assert.Len(dst.members, 2) {
+					assert.Equal(glab.OwnerPermissions, dst.members[0].AccessLevel)
+					assert.Equal("bob", dst.members[1].Username)
+				}
+			},
+		},
+	}
+	for _, run := range runs {
+		t.Run(run.name, func(t *testing.T) {
+			conf, err := config.Parse(strings.NewReader(run.config))
+			require.NoError(err)
+			// Load the conf.
+			m, err := New(conf)
+			require.NoError(err)
+			_, err = m.SourceProject(m.params.SrcPrj.Name)
+			require.NoError(err)
+			_, err = m.DestProject(m.params.DstPrj.Name)
+			require.NoError(err)
+			// Setup.
+			run.setup(source(m), dest(m))
+			// Run the migration.
+			err = m.Migrate()
+			// Asserts and tear down.
+			run.asserts(err, source(m), dest(m))
+		})
+	}
+}
+
+func makeMembers(names ...string) []*glab.ProjectMember {
+	members := make([]*glab.ProjectMember, len(names))
+	for k, n := range names {
+		members[k] = &glab.ProjectMember{
+			ID:          100 + k,
+			Username:    n,
+			AccessLevel: glab.DeveloperPermissions,
+		}
+	}
+	return members
+}
@@ -0,0 +1,218 @@
+package migration
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	glab "github.com/xanzy/go-gitlab"
+)
+
+var (
+	errDuplicateMergeRequest = errors.New("Duplicate Merge Request")
+)
+
+// branchExists returns true if branch exists in the target repository.
+func (m *Migration) branchExists(branch string) (bool, error) {
+	_, resp, err := m.Endpoint.DstClient.GetBranch(m.dstProject.ID, branch)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return false, nil
+		}
+		return false, fmt.Errorf("target: error getting branch '%s': %s", branch, err.Error())
+	}
+	return true, nil
+}
+
+// mergeRequestAssigneeIDs returns the target IDs of the merge request
+// assignees, if any.
+func (m *Migration) mergeRequestAssigneeIDs(mr *glab.MergeRequest) (*[]int, error) {
+	names := make([]string, 0)
+	for _, a := range mr.Assignees {
+		if a != nil && a.Username != "" {
+			names = append(names, a.Username)
+		}
+	}
+	return m.targetAssigneeIDs(names, fmt.Sprintf("merge request !%d", mr.IID))
+}
+
+func (m *Migration) migrateMergeRequest(mrID int) error {
+	source := m.Endpoint.SrcClient
+	target := m.Endpoint.DstClient
+
+	srcProjectID := m.srcProject.ID
+	tarProjectID := m.dstProject.ID
+
+	mr, _, err := source.GetMergeRequest(srcProjectID, mrID, nil)
+	if err != nil {
+		return fmt.Errorf("source: can't fetch merge request: %s", err.Error())
+	}
+	tmrs, _, err := target.ListProjectMergeRequests(tarProjectID, nil)
+	if err != nil {
+		return fmt.Errorf("target: can't fetch merge requests: %s", err.Error())
+	}
+	for _, t := range tmrs {
+		if mr.Title == t.Title {
+			// Target merge request already exists, let's skip this one.
+			return errDuplicateMergeRequest
+		}
+	}
+	ds, err := m.mergeRequestDiscussions(mr.IID)
+	if err != nil {
+		return err
+	}
+
+	// A merged merge request can't be recreated as merged: its changes are
+	// already part of the target branch.
+	recreate := mr.State != "merged"
+	for _, b := range []string{mr.SourceBranch, mr.TargetBranch} {
+		if !recreate {
+			break
+		}
+		recreate, err = m.branchExists(b)
+		if err != nil {
+			return err
+		}
+	}
+	if !recreate {
+		return m.mergeRequestAsIssue(mr, ds)
+	}
+
+	labels := mr.Labels
+	desc := m.rewriteUploads(mr.Description)
+	mopts := &glab.CreateMergeRequestOptions{
+		Title:        &mr.Title,
+		Description:  &desc,
+		SourceBranch: &mr.SourceBranch,
+		TargetBranch: &mr.TargetBranch,
+		Labels:       &labels,
+	}
+	if mopts.AssigneeIDs, err = m.mergeRequestAssigneeIDs(mr); err != nil {
+		return err
+	}
+	if mr.Milestone != nil && mr.Milestone.Title != "" {
+		if mopts.MilestoneID, err = m.targetMilestoneID(mr.Milestone); err != nil {
+			return err
+		}
+	}
+	nmr, _, err := target.CreateMergeRequest(tarProjectID, mopts)
+	if err != nil {
+		return fmt.Errorf("target: error creating merge request: %s", err.Error())
+	}
+
+	w := &mergeRequestThreads{target: target, pid: tarProjectID, iid: nmr.IID}
+	if _, err := m.copyDiscussions(w, ds); err != nil {
+		return err
+	}
+
+	if mr.State == "closed" || mr.State == "locked" {
+		event := "close"
+		_, _, err := target.UpdateMergeRequest(tarProjectID, nmr.IID,
+			&glab.UpdateMergeRequestOptions{StateEvent: &event})
+		if err != nil {
+			return fmt.Errorf("target: error closing merge request !%d: %s", nmr.IID, err.Error())
+		}
+	}
+
+	fmt.Printf("target: created merge request !%d: %s [%s]\n", nmr.IID, nmr.Title, mr.State)
+	return nil
+}
+
+// mergeRequestAsIssue creates an issue on target keeping the merge request's
+// title, description, state, labels, milestone and discussions. Used when the
+// merge request can't be recreated as is on target.
+func (m *Migration) mergeRequestAsIssue(mr *glab.MergeRequest, ds []*glab.Discussion) error {
+	target := m.Endpoint.DstClient
+	tarProjectID := m.dstProject.ID
+
+	tis, _, err := target.ListProjectIssues(tarProjectID, nil)
+	if err != nil {
+		return fmt.Errorf("target: can't fetch issue: %s", err.Error())
+	}
+	for _, t := range tis {
+		if mr.Title == t.Title {
+			return errDuplicateMergeRequest
+		}
+	}
+
+	head := fmt.Sprintf("Merge request !%d from `%s` into `%s` (%s)", mr.IID, mr.SourceBranch, mr.TargetBranch, mr.State)
+	if mr.Author != nil && mr.CreatedAt != nil {
+		head = fmt.Sprintf("%s, opened by %s @%s on %s", head, mr.Author.Name, mr.Author.Username, mr.CreatedAt.Format(time.RFC1123))
+	}
+	desc := fmt.Sprintf("%s\n\n%s", head, m.rewriteUploads(mr.Description))
+	labels := mr.Labels
+	iopts := &glab.CreateIssueOptions{
+		Title:       &mr.Title,
+		Description: &desc,
+		Labels:      &labels,
+	}
+	if iopts.AssigneeIDs, err = m.mergeRequestAssigneeIDs(mr); err != nil {
+		return err
+	}
+	if mr.Milestone != nil && mr.Milestone.Title != "" {
+		if iopts.MilestoneID, err = m.targetMilestoneID(mr.Milestone); err != nil {
+			return err
+		}
+	}
+	ni, _, err := target.CreateIssue(tarProjectID, iopts)
+	if err != nil {
+		return fmt.Errorf("target: error creating issue for merge request !%d: %s", mr.IID, err.Error())
+	}
+	if _, err := m.copyDiscussions(&issueThreads{pid: tarProjectID, iid: ni.IID}, ds); err != nil {
+		return err
+	}
+	if mr.State != "opened" {
+		event := "close"
+		_, _, err := target.UpdateIssue(tarProjectID, ni.IID,
+			&glab.UpdateIssueOptions{StateEvent: &event, Labels: &labels})
+		if err != nil {
+			return fmt.Errorf("target: error closing issue #%d: %s", ni.IID, err.Error())
+		}
+	}
+
+	fmt.Printf("target: created issue #%d from merge request !%d: %s [%s]\n", ni.IID, mr.IID, ni.Title, mr.State)
+	return nil
+}
+
+// migrateMergeRequests copies all source merge requests to target, sorted by
+// IID.
+func (m *Migration) migrateMergeRequests() error {
+	source := m.Endpoint.SrcClient
+	srcProjectID := m.srcProject.ID
+
+	fmt.Println("Copying merge requests ...")
+
+	curPage := 1
+	optSort := "asc"
+	opts := &glab.ListProjectMergeRequestsOptions{Sort: &optSort, ListOptions: glab.ListOptions{PerPage: ResultsPerPage, Page: curPage}}
+
+	s := make([]issueID, 0)
+	for {
+		mrs, _, err := source.ListProjectMergeRequests(srcProjectID, opts)
+		if err != nil {
+			return fmt.Errorf("source: can't fetch merge requests: %s", err.Error())
+		}
+		if len(mrs) == 0 {
+			break
+		}
+		for _, mr := range mrs {
+			s = append(s, issueID{IID: mr.IID, ID: mr.ID})
+		}
+		curPage++
+		opts.Page = curPage
+	}
+	sort.Sort(byIID(s))
+
+	for _, mr := range s {
+		if err := m.migrateMergeRequest(mr.IID); err != nil {
+			if err == errDuplicateMergeRequest {
+				fmt.Printf("target: merge request %d already exists, skipping...\n", mr.IID)
+				continue
+			}
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,248 @@
+package migration
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/gotsunami/gitlab-copy/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	glab "github.com/xanzy/go-gitlab"
+)
+
+func TestMigrateMergeRequest(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	runs := []struct {
+		name    string                     // Sub-test name
+		config  string                     // YAML config
+		setup   func(src, dst *fakeClient) // Defines any option before calling Migrate()
+		asserts func(err error, src, dst *fakeClient)
+	}{
+		{
+			"Get merge request fails",
+			cfg5,
+			func(src, dst *fakeClient) {
+				src.mergeRequests = makeMergeRequests("mr1")
+				src.errors.getMergeRequest = errors.New("err")
+			},
+			func(err error, src, dst *fakeClient) {
+				assert.Error(err)
+			},
+		},
+		{
+			"Duplicate merge request",
+			cfg5,
+			func(src, dst *fakeClient) {
+				src.mergeRequests = makeMergeRequests("mr1")
+				dst.mergeRequests = makeMergeRequests("mr1")
+			},
+			func(err error, src, dst *fakeClient) {
+				assert.Equal(errDuplicateMergeRequest, err)
+			},
+		},
+		{
+			"Branches exist on target",
+			cfg5,
+			func(src, dst *fakeClient) {
+				src.mergeRequests = makeMergeRequests("mr1")
+				src.discussions = makeDiscussions(makeNotes("n1", "n2")...)
+				dst.branches = []string{"feature", "main"}
+			},
+			func(err error, src, dst *fakeClient) {
+				require.NoError(err)
+				if assert.Len(dst.mergeRequests, 1) {
+					assert.Equal("mr1", dst.mergeRequests[0].Title)
+					assert.Equal("feature", dst.mergeRequests[0].SourceBranch)
+				}
+				assert.Len(dst.mergeRequestNotes, 2)
+				assert.Empty(dst.issues)
+			},
+		},
+		{
+			"Resolved thread stays resolved",
+			cfg5,
+			func(src, dst *fakeClient) {
+				src.mergeRequests = makeMergeRequests("mr1")
+				notes := makeNotes("n1", "n2")
+				for _, n := range notes {
+					n.Resolvable = true
+					n.Resolved = true
+				}
+				src.discussions = []*glab.Discussion{
+					makeThread(notes...),
+					makeThread(makeNotes("n3")...),
+				}
+				dst.branches = []string{"feature", "main"}
+			},
+			func(err error, src, dst *fakeClient) {
+				require.NoError(err)
+				if assert.Len(dst.discussions, 2) {
+					assert.Len(dst.discussions[0].Notes, 2)
+					assert.True(dst.discussions[0].Notes[0].Resolved)
+					assert.False(dst.discussions[1].Notes[0].Resolved)
+				}
+			},
+		},
+		{
+			"Resolving thread fails",
+			cfg5,
+			func(src, dst *fakeClient) {
+				src.mergeRequests = makeMergeRequests("mr1")
+				notes := makeNotes("n1")
+				notes[0].Resolvable = true
+				notes[0].Resolved = true
+				src.discussions = []*glab.Discussion{makeThread(notes...)}
+				dst.branches = []string{"feature", "main"}
+				dst.errors.resolveDiscussion = errors.New("err")
+			},
+			func(err error, src, dst *fakeClient) {
+				assert.Error(err)
+			},
+		},
+		{
+			"Closed merge request, branches exist on target",
+			cfg5,
+			func(src, dst *fakeClient) {
+				src.mergeRequests = makeMergeRequests("mr1")
+				src.mergeRequests[0].State = "closed"
+				dst.branches = []string{"feature", "main"}
+			},
+			func(err error, src, dst *fakeClient) {
+				require.NoError(err)
+				if assert.Len(dst.mergeRequests, 1) {
+					assert.Equal("close", dst.mergeRequests[0].State)
+				}
+			},
+		},
+		{
+			"Source branch missing on target, fallback to issue",
+			cfg5,
+			func(src, dst *fakeClient) {
+				src.mergeRequests = makeMergeRequests("mr1")
+				dst.branches = []string{"main"}
+			},
+			func(err error, src, dst *fakeClient) {
+				require.NoError(err)
+				assert.Empty(dst.mergeRequests)
+				if assert.Len(dst.issues, 1) {
+					assert.Equal("mr1", dst.issues[0].Title)
+				}
+			},
+		},
+		{
+			"Merged merge request, fallback to issue",
+			cfg5,
+			func(src, dst *fakeClient) {
+				src.mergeRequests = makeMergeRequests("mr1")
+				src.mergeRequests[0].State = "merged"
+				dst.branches = []string{"feature", "main"}
+			},
+			func(err error, src, dst *fakeClient) {
+				require.NoError(err)
+				assert.Empty(dst.mergeRequests)
+				assert.Len(dst.issues, 1)
+			},
+		},
+		{
+			"Fallback issue already exists",
+			cfg5,
+			func(src, dst *fakeClient) {
+				src.mergeRequests = makeMergeRequests("mr1")
+				dst.issues = makeIssues("mr1")
+			},
+			func(err error, src, dst *fakeClient) {
+				assert.Equal(errDuplicateMergeRequest, err)
+			},
+		},
+		{
+			"Getting target branch fails",
+			cfg5,
+			func(src, dst *fakeClient) {
+				src.mergeRequests = makeMergeRequests("mr1")
+				dst.errors.getBranch = errors.New("err")
+			},
+			func(err error, src, dst *fakeClient) {
+				assert.Error(err)
+			},
+		},
+		{
+			"Creating merge request fails",
+			cfg5,
+			func(src, dst *fakeClient) {
+				src.mergeRequests = makeMergeRequests("mr1")
+				dst.branches = []string{"feature", "main"}
+				dst.errors.createMergeRequest = errors.New("err")
+			},
+			func(err error, src, dst *fakeClient) {
+				assert.Error(err)
+			},
+		},
+		{
+			"Creating merge request note fails",
+			cfg5,
+			func(src, dst *fakeClient) {
+				src.mergeRequests = makeMergeRequests("mr1")
+				src.discussions = makeDiscussions(makeNotes("n1")...)
+				dst.branches = []string{"feature", "main"}
+				dst.errors.createMergeRequestNote = errors.New("err")
+			},
+			func(err error, src, dst *fakeClient) {
+				assert.Error(err)
+			},
+		},
+	}
+	for _, run := range runs {
+		t.Run(run.name, func(t *testing.T) {
+			conf, err := config.Parse(strings.NewReader(run.config))
+			require.NoError(err)
+			// Load the conf.
+			m, err := New(conf)
+			require.NoError(err)
+			_, err = m.SourceProject(m.params.SrcPrj.Name)
+			require.NoError(err)
+			_, err = m.DestProject(m.params.DstPrj.Name)
+			require.NoError(err)
+			// Setup.
+			run.setup(source(m), dest(m))
+			// Run the migration.
+			err = m.migrateMergeRequest(0)
+			// Asserts and tear down.
+			run.asserts(err, source(m), dest(m))
+		})
+	}
+}
+
+func TestMigrateMergeRequests(t *testing.T) {
+	require := require.New(t)
+
+	conf, err := config.Parse(strings.NewReader(cfg5))
+	require.NoError(err)
+	m, err := New(conf)
+	require.NoError(err)
+	src, dst := source(m), dest(m)
+	src.issues = makeIssues("issue1")
+	src.mergeRequests = makeMergeRequests("mr1", "mr2")
+	dst.branches = []string{"feature", "main"}
+
+	require.NoError(m.Migrate())
+	require.Len(dst.issues, 1)
+	require.Len(dst.mergeRequests, 2)
+}
+
+func makeMergeRequests(names ...string) []*glab.MergeRequest {
+	mrs := make([]*glab.MergeRequest, len(names))
+	for k, n := range names {
+		mrs[k] = &glab.MergeRequest{
+			ID:           k,
+			IID:          k,
+			Title:        n,
+			State:        "opened",
+			SourceBranch: "feature",
+			TargetBranch: "main",
+		}
+	}
+	return mrs
+}
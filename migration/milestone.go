@@ -0,0 +1,136 @@
+package migration
+
+import (
+	"fmt"
+
+	"github.com/gotsunami/gitlab-copy/gitlab"
+	glab "github.com/xanzy/go-gitlab"
+)
+
+// listMilestones returns all milestones of project pid, fetching all pages.
+func listMilestones(c gitlab.GitLaber, pid int) ([]*glab.Milestone, error) {
+	all := make([]*glab.Milestone, 0)
+	opts := &glab.ListMilestonesOptions{ListOptions: glab.ListOptions{PerPage: ResultsPerPage, Page: 1}}
+	for {
+		ms, _, err := c.ListMilestones(pid, opts)
+		if err != nil {
+			return nil, err
+		}
+		if len(ms) == 0 {
+			break
+		}
+		all = append(all, ms...)
+		opts.Page++
+	}
+	return all, nil
+}
+
+// sameDate checks whether a and b are the same date, or both unset.
+func sameDate(a, b *glab.ISOTime) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.String() == b.String()
+}
+
+// createMilestone creates the source milestone mi on target, with its
+// description, dates and state.
+func (m *Migration) createMilestone(mi *glab.Milestone) (*glab.Milestone, error) {
+	target := m.Endpoint.DstClient
+	tarProjectID := m.dstProject.ID
+
+	cmopts := &glab.CreateMilestoneOptions{
+		Title:       &mi.Title,
+		Description: &mi.Description,
+		StartDate:   mi.StartDate,
+		DueDate:     mi.DueDate,
+	}
+	tmi, _, err := target.CreateMilestone(tarProjectID, cmopts)
+	if err != nil {
+		return nil, fmt.Errorf("target: error creating milestone '%s': %s", mi.Title, err.Error())
+	}
+	if mi.State == "closed" {
+		event := "close"
+		umopts := &glab.UpdateMilestoneOptions{StateEvent: &event}
+		if _, _, err := target.UpdateMilestone(tarProjectID, tmi.ID, umopts); err != nil {
+			return nil, fmt.Errorf("target: error closing milestone '%s': %s", mi.Title, err.Error())
+		}
+	}
+	m.milestones[mi.Title] = tmi.ID
+	return tmi, nil
+}
+
+// reconcileMilestone updates the target milestone tmi whose description,
+// dates or state differ from the source milestone mi. Dates set on target
+// only are kept. Returns true if tmi has been updated.
+func (m *Migration) reconcileMilestone(mi, tmi *glab.Milestone) (bool, error) {
+	umopts := new(glab.UpdateMilestoneOptions)
+	changed := false
+	if mi.Description != tmi.Description {
+		umopts.Description = &mi.Description
+		changed = true
+	}
+	if mi.StartDate != nil && !sameDate(mi.StartDate, tmi.StartDate) {
+		umopts.StartDate = mi.StartDate
+		changed = true
+	}
+	if mi.DueDate != nil && !sameDate(mi.DueDate, tmi.DueDate) {
+		umopts.DueDate = mi.DueDate
+		changed = true
+	}
+	if (mi.State == "closed") != (tmi.State == "closed") {
+		event := "activate"
+		if mi.State == "closed" {
+			event = "close"
+		}
+		umopts.StateEvent = &event
+		changed = true
+	}
+	if !changed {
+		return false, nil
+	}
+	if _, _, err := m.Endpoint.DstClient.UpdateMilestone(m.dstProject.ID, tmi.ID, umopts); err != nil {
+		return false, fmt.Errorf("target: error updating milestone '%s': %s", mi.Title, err.Error())
+	}
+	return true, nil
+}
+
+// migrateMilestones copies the source milestones on target, with their
+// description, dates and state. Milestones already existing on target (by
+// title) are updated if they differ.
+func (m *Migration) migrateMilestones() error {
+	fmt.Println("Copying milestones ...")
+	miles, err := listMilestones(m.Endpoint.SrcClient, m.srcProject.ID)
+	if err != nil {
+		return fmt.Errorf("source: can't fetch milestones: %s", err.Error())
+	}
+	fmt.Printf("Found %d milestones\n", len(miles))
+	tms, err := listMilestones(m.Endpoint.DstClient, m.dstProject.ID)
+	if err != nil {
+		return fmt.Errorf("target: can't fetch milestones: %s", err.Error())
+	}
+	existing := make(map[string]*glab.Milestone)
+	for _, tmi := range tms {
+		existing[tmi.Title] = tmi
+	}
+
+	for _, mi := range miles {
+		tmi, ok := existing[mi.Title]
+		if !ok {
+			if _, err := m.createMilestone(mi); err != nil {
+				return err
+			}
+			fmt.Printf("target: created milestone %s [%s]\n", mi.Title, mi.State)
+			continue
+		}
+		m.milestones[mi.Title] = tmi.ID
+		updated, err := m.reconcileMilestone(mi, tmi)
+		if err != nil {
+			return err
+		}
+		if updated {
+			fmt.Printf("target: updated milestone %s [%s]\n", mi.Title, mi.State)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package migration
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gotsunami/gitlab-copy/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	glab "github.com/xanzy/go-gitlab"
+)
+
+func TestMigrateMilestones(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	date := func(day int) *glab.ISOTime {
+		d := glab.ISOTime(time.Date(2023, 1, day, 0, 0, 0, 0, time.UTC))
+		return &d
+	}
+
+	runs := []struct {
+		name    string                     // Sub-test name
+		config  string                     // YAML config
+		setup   func(src, dst *fakeClient) // Defines any option before calling Migrate()
+		asserts func(err error, src, dst *fakeClient)
+	}{
+		{
+			"Milestones created with their dates and state",
+			cfg3,
+			func(src, dst *fakeClient) {
+				src.milestones = []*glab.Milestone{
+					{Title: "v1", Description: "first", StartDate: date(1), DueDate: date(15), State: "closed"},
+					{Title: "v2", State: "active"},
+				}
+			},
+			func(err error, src, dst *fakeClient) {
+				require.NoError(err)
+				if assert.Len(dst.milestones, 2) {
+					mi := dst.milestones[0]
+					assert.Equal("first", mi.Description)
+					assert.Equal(date(1), mi.StartDate)
+					assert.Equal(date(15), mi.DueDate)
+					assert.Equal("closed", mi.State)
+					assert.Equal("active", dst.milestones[1].State)
+				}
+			},
+		},
+		{
+			"Existing milestones reconciled",
+			cfg3,
+			func(src, dst *fakeClient) {
+				src.milestones = []*glab.Milestone{
+					{Title: "v1", Description: "first", DueDate: date(15), State: "closed"},
+					{Title: "v2", Description: "second", State: "active"},
+					{Title: "v3", State: "active"},
+				}
+				dst.milestones = []*glab.Milestone{
+					{ID: 0, Title: "v1", DueDate: date(10), State: "active"},
+					{ID: 1, Title: "v2", Description: "second", StartDate: date(2), State: "closed"},
+					{ID: 2, Title: "v3", State: "active"},
+				}
+			},
+			func(err error, src, dst *fakeClient) {
+				require.NoError(err)
+				if assert.Len(dst.milestones, 3) {
+					mi := dst.milestones[0]
+					assert.Equal("first", mi.Description)
+					assert.Equal(date(15), mi.DueDate)
+					assert.Equal("closed", mi.State)
+					mi = dst.milestones[1]
+					assert.Equal("active", mi.State)
+					// Kept, as the source milestone has no start date.
+					assert.Equal(date(2), mi.StartDate)
+				}
+			},
+		},
+		{
+			"Updating milestone fails",
+			cfg3,
+			func(src, dst *fakeClient) {
+				src.milestones = []*glab.Milestone{{Title: "v1", Description: "first"}}
+				dst.milestones = []*glab.Milestone{{Title: "v1"}}
+				dst.errors.updateMilestone = errors.New("err")
+			},
+			func(err error, src, dst *fakeClient) {
+				assert.Error(err)
+			},
+		},
+		{
+			"Listing target milestones fails",
+			cfg3,
+			func(src, dst *fakeClient) {
+				dst.errors.listMilestones = errors.New("err")
+			},
+			func(err error, src, dst *fakeClient) {
+				assert.Error(err)
+			},
+		},
+		{
+			"Milestones synced before issues use them",
+			cfg2,
+			func(src, dst *fakeClient) {
+				src.milestones = []*glab.Milestone{{Title: "v1", StartDate: date(1), State: "closed"}}
+				src.issues = makeIssues("issue1")
+				src.issues[0].Milestone = &glab.Milestone{Title: "v1", State: "closed"}
+			},
+			func(err error, src, dst *fakeClient) {
+				require.NoError(err)
+				if assert.Len(dst.milestones, 1) {
+					assert.Equal(date(1), dst.milestones[0].StartDate)
+					assert.Equal("closed", dst.milestones[0].State)
+				}
+			},
+		},
+	}
+	for _, run := range runs {
+		t.Run(run.name, func(t *testing.T) {
+			conf, err := config.Parse(strings.NewReader(run.config))
+			require.NoError(err)
+			// Load the conf.
+			m, err := New(conf)
+			require.NoError(err)
+			_, err = m.SourceProject(m.params.SrcPrj.Name)
+			require.NoError(err)
+			_, err = m.DestProject(m.params.DstPrj.Name)
+			require.NoError(err)
+			// Setup.
+			run.setup(source(m), dest(m))
+			// Run the migration.
+			err = m.Migrate()
+			// Asserts and tear down.
+			run.asserts(err, source(m), dest(m))
+		})
+	}
+}
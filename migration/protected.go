@@ -0,0 +1,514 @@
+package migration
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gotsunami/gitlab-copy/gitlab"
+	glab "github.com/xanzy/go-gitlab"
+)
+
+// accessGrant is a source access level entry: a role, a user, a group or a
+// deploy key.
+type accessGrant struct {
+	level                        glab.AccessLevelValue
+	userID, groupID, deployKeyID int
+}
+
+// targetGroupPath returns the path of the target group matching the source
+// group path. Source paths under the configured source group are moved under
+// the configured target group.
+func (m *Migration) targetGroupPath(srcPath string) string {
+	src, dst := m.params.SrcPrj.Group, m.params.DstPrj.Group
+	if src == "" || dst == "" {
+		return srcPath
+	}
+	if srcPath == src || strings.HasPrefix(srcPath, src+"/") {
+		return dst + strings.TrimPrefix(srcPath, src)
+	}
+	return srcPath
+}
+
+// targetGroup returns the ID of the target group matching the source group
+// path, or nil if no such group exists on target.
+func (m *Migration) targetGroup(srcPath string) (*int, error) {
+	tg, resp, err := m.Endpoint.DstClient.GetGroup(m.targetGroupPath(srcPath), nil)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("target: can't get group %s: %s", srcPath, err.Error())
+	}
+	return &tg.ID, nil
+}
+
+// targetGroupID returns the ID of the target group matching the source group
+// gid, or nil if no such group exists on target. The path of the source group
+// is returned too.
+func (m *Migration) targetGroupID(gid int) (*int, string, error) {
+	g, _, err := m.Endpoint.SrcClient.GetGroup(gid, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("source: can't get group %d: %s", gid, err.Error())
+	}
+	id, err := m.targetGroup(g.FullPath)
+	if err != nil {
+		return nil, "", err
+	}
+	return id, g.FullPath, nil
+}
+
+// remapGrants returns the target counterparts of the source grants. The first
+// role is returned apart, since GitLab expects it in its own option. Users and
+// groups without a match on target are listed in missing. If none of the
+// grants has a match, the role is set to no access, since GitLab would fall
+// back to Maintainers otherwise. Deploy keys are project specific, so they
+// are never copied and listed in missing too.
+func (m *Migration) remapGrants(grants []accessGrant) (role *glab.AccessLevelValue, allowed []accessGrant, missing []string, err error) {
+	allowed = make([]accessGrant, 0)
+	missing = make([]string, 0)
+	for _, g := range grants {
+		switch {
+		case g.deployKeyID != 0:
+			missing = append(missing, fmt.Sprintf("deploy key %d", g.deployKeyID))
+		case g.userID != 0:
+			u, _, err := m.Endpoint.SrcClient.GetUser(g.userID, glab.GetUsersOptions{})
+			if err != nil {
+				return nil, nil, nil, fmt.Errorf("source: can't get user %d: %s", g.userID, err.Error())
+			}
+			uid, err := m.targetUserID(u.Username)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			if uid == nil {
+				missing = append(missing, "@"+u.Username)
+				continue
+			}
+			allowed = append(allowed, accessGrant{userID: *uid})
+		case g.groupID != 0:
+			gid, name, err := m.targetGroupID(g.groupID)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			if gid == nil {
+				missing = append(missing, "group "+name)
+				continue
+			}
+			allowed = append(allowed, accessGrant{groupID: *gid})
+		case role == nil:
+			level := g.level
+			role = &level
+		default:
+			allowed = append(allowed, accessGrant{level: g.level})
+		}
+	}
+	if len(grants) > 0 && role == nil && len(allowed) == 0 {
+		role = glab.AccessLevel(glab.NoPermissions)
+	}
+	return role, allowed, missing, nil
+}
+
+// branchGrants converts branch access descriptions to grants. keys holds the
+// deploy keys of the access descriptions, by ID.
+func branchGrants(ds []*glab.BranchAccessDescription, keys map[int]int) []accessGrant {
+	gs := make([]accessGrant, len(ds))
+	for k, d := range ds {
+		if key, ok := keys[d.ID]; ok && d.ID != 0 {
+			gs[k] = accessGrant{deployKeyID: key}
+			continue
+		}
+		gs[k] = accessGrant{level: d.AccessLevel, userID: d.UserID, groupID: d.GroupID}
+	}
+	return gs
+}
+
+// splitGrants returns the first role of gs apart from the other grants, as
+// GitLab expects it.
+func splitGrants(gs []accessGrant) (*glab.AccessLevelValue, []accessGrant) {
+	allowed := make([]accessGrant, 0)
+	var role *glab.AccessLevelValue
+	for _, g := range gs {
+		if role == nil && g.userID == 0 && g.groupID == 0 && g.deployKeyID == 0 {
+			level := g.level
+			role = &level
+			continue
+		}
+		allowed = append(allowed, g)
+	}
+	return role, allowed
+}
+
+// options returns the access level, user ID and group ID options of g, only
+// one of them being set.
+func (g accessGrant) options() (*glab.AccessLevelValue, *int, *int) {
+	switch {
+	case g.userID != 0:
+		return nil, glab.Int(g.userID), nil
+	case g.groupID != 0:
+		return nil, nil, glab.Int(g.groupID)
+	}
+	return glab.AccessLevel(g.level), nil, nil
+}
+
+// branchPermissions converts grants to branch permission options.
+func branchPermissions(gs []accessGrant) *[]*glab.BranchPermissionOptions {
+	if len(gs) == 0 {
+		return nil
+	}
+	ps := make([]*glab.BranchPermissionOptions, len(gs))
+	for k, g := range gs {
+		p := new(glab.BranchPermissionOptions)
+		if g.deployKeyID != 0 {
+			p.DeployKeyID = glab.Int(g.deployKeyID)
+		} else {
+			p.AccessLevel, p.UserID, p.GroupID = g.options()
+		}
+		ps[k] = p
+	}
+	return &ps
+}
+
+// tagPermissions converts grants to tag permission options.
+func tagPermissions(gs []accessGrant) *[]*glab.TagsPermissionOptions {
+	if len(gs) == 0 {
+		return nil
+	}
+	ps := make([]*glab.TagsPermissionOptions, len(gs))
+	for k, g := range gs {
+		p := new(glab.TagsPermissionOptions)
+		p.AccessLevel, p.UserID, p.GroupID = g.options()
+		ps[k] = p
+	}
+	return &ps
+}
+
+// grantsKey returns a key identifying the grants gs, whatever their order.
+// Deploy keys are left out, since they are never copied.
+func grantsKey(gs []accessGrant) string {
+	keys := make([]string, 0, len(gs))
+	for _, g := range gs {
+		switch {
+		case g.deployKeyID != 0:
+		case g.userID != 0:
+			keys = append(keys, fmt.Sprintf("user:%d", g.userID))
+		case g.groupID != 0:
+			keys = append(keys, fmt.Sprintf("group:%d", g.groupID))
+		default:
+			keys = append(keys, fmt.Sprintf("level:%d", g.level))
+		}
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, ",")
+}
+
+// sameGrants checks whether the target grants match the wanted ones.
+// No wanted grant at all means GitLab's default, which always matches.
+func sameGrants(want, got []accessGrant) bool {
+	return len(want) == 0 || grantsKey(want) == grantsKey(got)
+}
+
+// remapLevel returns the target counterparts of the source grants, as
+// returned by remapGrants, along with all of them in a single list.
+func (m *Migration) remapLevel(grants []accessGrant) (*glab.AccessLevelValue, []accessGrant, []accessGrant, []string, error) {
+	role, allowed, missing, err := m.remapGrants(grants)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	all := allowed
+	if role != nil {
+		all = append([]accessGrant{{level: *role}}, allowed...)
+	}
+	return role, allowed, all, missing, nil
+}
+
+// migrateProtectedBranch protects the branch (or wildcard) of the source rule
+// pb on target, with the same push, merge and unprotect access levels. tpb is
+// the rule of the same name on target, if any, which is replaced if its
+// access levels differ. keys and tkeys hold the deploy keys of the source and
+// target access levels.
+func (m *Migration) migrateProtectedBranch(pb, tpb *glab.ProtectedBranch, keys, tkeys map[int]int) error {
+	target := m.Endpoint.DstClient
+	tarProjectID := m.dstProject.ID
+
+	missing := make([]string, 0)
+	opts := &glab.ProtectRepositoryBranchesOptions{
+		Name:                      &pb.Name,
+		AllowForcePush:            &pb.AllowForcePush,
+		CodeOwnerApprovalRequired: &pb.CodeOwnerApprovalRequired,
+	}
+	same := tpb != nil &&
+		tpb.AllowForcePush == pb.AllowForcePush &&
+		tpb.CodeOwnerApprovalRequired == pb.CodeOwnerApprovalRequired
+	levels := []struct {
+		grants  []*glab.BranchAccessDescription
+		current []*glab.BranchAccessDescription
+		role    **glab.AccessLevelValue
+		allowed **[]*glab.BranchPermissionOptions
+	}{
+		{pb.PushAccessLevels, nil, &opts.PushAccessLevel, &opts.AllowedToPush},
+		{pb.MergeAccessLevels, nil, &opts.MergeAccessLevel, &opts.AllowedToMerge},
+		{pb.UnprotectAccessLevels, nil, &opts.UnprotectAccessLevel, &opts.AllowedToUnprotect},
+	}
+	if tpb != nil {
+		levels[0].current = tpb.PushAccessLevels
+		levels[1].current = tpb.MergeAccessLevels
+		levels[2].current = tpb.UnprotectAccessLevels
+	}
+	for _, l := range levels {
+		role, allowed, all, miss, err := m.remapLevel(branchGrants(l.grants, keys))
+		if err != nil {
+			return err
+		}
+		*l.role = role
+		*l.allowed = branchPermissions(allowed)
+		missing = append(missing, miss...)
+		same = same && sameGrants(all, branchGrants(l.current, tkeys))
+	}
+
+	if tpb != nil {
+		if same {
+			fmt.Printf("target: branch %s already protected with the same access levels, skipping...\n", pb.Name)
+			return nil
+		}
+		if _, err := target.UnprotectRepositoryBranches(tarProjectID, pb.Name); err != nil {
+			return fmt.Errorf("target: error unprotecting branch '%s': %s", pb.Name, err.Error())
+		}
+	}
+	if _, _, err := target.ProtectRepositoryBranches(tarProjectID, opts); err != nil {
+		if tpb != nil {
+			return m.restoreProtectedBranch(tpb, tkeys, err)
+		}
+		return fmt.Errorf("target: error protecting branch '%s': %s", pb.Name, err.Error())
+	}
+	if tpb != nil {
+		fmt.Printf("target: branch %s protected with other access levels, protected again\n", pb.Name)
+	} else {
+		fmt.Printf("target: protected branch %s\n", pb.Name)
+	}
+	if len(missing) > 0 {
+		fmt.Printf("target: branch %s: no match for %s, access not granted\n", pb.Name, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// restoreProtectedBranch protects the branch of the target rule tpb again,
+// with its former access levels, after protecting it with the source ones
+// failed with perr. The returned error tells whether the branch is left
+// unprotected.
+func (m *Migration) restoreProtectedBranch(tpb *glab.ProtectedBranch, tkeys map[int]int, perr error) error {
+	opts := &glab.ProtectRepositoryBranchesOptions{
+		Name:                      &tpb.Name,
+		AllowForcePush:            &tpb.AllowForcePush,
+		CodeOwnerApprovalRequired: &tpb.CodeOwnerApprovalRequired,
+	}
+	levels := []struct {
+		current []*glab.BranchAccessDescription
+		role    **glab.AccessLevelValue
+		allowed **[]*glab.BranchPermissionOptions
+	}{
+		{tpb.PushAccessLevels, &opts.PushAccessLevel, &opts.AllowedToPush},
+		{tpb.MergeAccessLevels, &opts.MergeAccessLevel, &opts.AllowedToMerge},
+		{tpb.UnprotectAccessLevels, &opts.UnprotectAccessLevel, &opts.AllowedToUnprotect},
+	}
+	for _, l := range levels {
+		role, allowed := splitGrants(branchGrants(l.current, tkeys))
+		*l.role = role
+		*l.allowed = branchPermissions(allowed)
+	}
+	if _, _, err := m.Endpoint.DstClient.ProtectRepositoryBranches(m.dstProject.ID, opts); err != nil {
+		return fmt.Errorf("target: error protecting branch '%s': %s; branch left unprotected on target, restoring its former access levels failed: %s",
+			tpb.Name, perr.Error(), err.Error())
+	}
+	return fmt.Errorf("target: error protecting branch '%s': %s; former access levels restored", tpb.Name, perr.Error())
+}
+
+// tagGrants converts tag access descriptions to grants.
+func tagGrants(ds []*glab.TagAccessDescription) []accessGrant {
+	gs := make([]accessGrant, len(ds))
+	for k, d := range ds {
+		gs[k] = accessGrant{level: d.AccessLevel, userID: d.UserID, groupID: d.GroupID}
+	}
+	return gs
+}
+
+// migrateProtectedTag protects the tag (or wildcard) of the source rule pt on
+// target, with the same create access levels. tpt is the rule of the same name
+// on target, if any, which is replaced if its access levels differ.
+func (m *Migration) migrateProtectedTag(pt, tpt *glab.ProtectedTag) error {
+	target := m.Endpoint.DstClient
+	tarProjectID := m.dstProject.ID
+
+	role, allowed, all, missing, err := m.remapLevel(tagGrants(pt.CreateAccessLevels))
+	if err != nil {
+		return err
+	}
+	if tpt != nil {
+		if sameGrants(all, tagGrants(tpt.CreateAccessLevels)) {
+			fmt.Printf("target: tag %s already protected with the same access levels, skipping...\n", pt.Name)
+			return nil
+		}
+		if _, err := target.UnprotectRepositoryTags(tarProjectID, pt.Name); err != nil {
+			return fmt.Errorf("target: error unprotecting tag '%s': %s", pt.Name, err.Error())
+		}
+	}
+	opts := &glab.ProtectRepositoryTagsOptions{
+		Name:              &pt.Name,
+		CreateAccessLevel: role,
+		AllowedToCreate:   tagPermissions(allowed),
+	}
+	if _, _, err := target.ProtectRepositoryTags(tarProjectID, opts); err != nil {
+		if tpt != nil {
+			return m.restoreProtectedTag(tpt, err)
+		}
+		return fmt.Errorf("target: error protecting tag '%s': %s", pt.Name, err.Error())
+	}
+	if tpt != nil {
+		fmt.Printf("target: tag %s protected with other access levels, protected again\n", pt.Name)
+	} else {
+		fmt.Printf("target: protected tag %s\n", pt.Name)
+	}
+	if len(missing) > 0 {
+		fmt.Printf("target: tag %s: no match for %s, access not granted\n", pt.Name, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// restoreProtectedTag protects the tag of the target rule tpt again, with its
+// former access levels, after protecting it with the source ones failed with
+// perr. The returned error tells whether the tag is left unprotected.
+func (m *Migration) restoreProtectedTag(tpt *glab.ProtectedTag, perr error) error {
+	role, allowed := splitGrants(tagGrants(tpt.CreateAccessLevels))
+	opts := &glab.ProtectRepositoryTagsOptions{
+		Name:              &tpt.Name,
+		CreateAccessLevel: role,
+		AllowedToCreate:   tagPermissions(allowed),
+	}
+	if _, _, err := m.Endpoint.DstClient.ProtectRepositoryTags(m.dstProject.ID, opts); err != nil {
+		return fmt.Errorf("target: error protecting tag '%s': %s; tag left unprotected on target, restoring its former access levels failed: %s",
+			tpt.Name, perr.Error(), err.Error())
+	}
+	return fmt.Errorf("target: error protecting tag '%s': %s; former access levels restored", tpt.Name, perr.Error())
+}
+
+// listProtectedBranches returns all protected branches of project pid,
+// fetching all pages.
+func listProtectedBranches(c gitlab.GitLaber, pid int) ([]*glab.ProtectedBranch, error) {
+	all := make([]*glab.ProtectedBranch, 0)
+	opts := &glab.ListProtectedBranchesOptions{ListOptions: glab.ListOptions{PerPage: ResultsPerPage, Page: 1}}
+	for {
+		pbs, _, err := c.ListProtectedBranches(pid, opts)
+		if err != nil {
+			return nil, err
+		}
+		if len(pbs) == 0 {
+			break
+		}
+		all = append(all, pbs...)
+		opts.Page++
+	}
+	return all, nil
+}
+
+// listBranchDeployKeys returns the deploy keys allowed by the protected
+// branches of project pid, by access level entry ID, fetching all pages.
+func listBranchDeployKeys(c gitlab.GitLaber, pid int) (map[int]int, error) {
+	all := make(map[int]int)
+	opts := &glab.ListProtectedBranchesOptions{ListOptions: glab.ListOptions{PerPage: ResultsPerPage, Page: 1}}
+	for {
+		keys, resp, err := c.ListProtectedBranchDeployKeys(pid, opts)
+		if err != nil {
+			return nil, err
+		}
+		for id, key := range keys {
+			all[id] = key
+		}
+		// A page may hold no deploy key, so rely on the next page header
+		// rather than on an empty result.
+		if resp == nil || resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return all, nil
+}
+
+// listProtectedTags returns all protected tags of project pid, fetching all
+// pages.
+func listProtectedTags(c gitlab.GitLaber, pid int) ([]*glab.ProtectedTag, error) {
+	all := make([]*glab.ProtectedTag, 0)
+	opts := &glab.ListProtectedTagsOptions{PerPage: ResultsPerPage, Page: 1}
+	for {
+		pts, _, err := c.ListProtectedTags(pid, opts)
+		if err != nil {
+			return nil, err
+		}
+		if len(pts) == 0 {
+			break
+		}
+		all = append(all, pts...)
+		opts.Page++
+	}
+	return all, nil
+}
+
+// migrateProtectedRefs copies the source protected branches and tags rules.
+// Rules already existing on target (by name) are replaced if their access
+// levels differ. Users and groups allowed by a rule are matched on target, by
+// username and group path.
+func (m *Migration) migrateProtectedRefs() error {
+	source := m.Endpoint.SrcClient
+	target := m.Endpoint.DstClient
+
+	srcProjectID := m.srcProject.ID
+	tarProjectID := m.dstProject.ID
+
+	fmt.Println("Copying protected branches ...")
+	pbs, err := listProtectedBranches(source, srcProjectID)
+	if err != nil {
+		return fmt.Errorf("source: can't fetch protected branches: %s", err.Error())
+	}
+	fmt.Printf("Found %d protected branches\n", len(pbs))
+	tpbs, err := listProtectedBranches(target, tarProjectID)
+	if err != nil {
+		return fmt.Errorf("target: can't fetch protected branches: %s", err.Error())
+	}
+	keys, err := listBranchDeployKeys(source, srcProjectID)
+	if err != nil {
+		return fmt.Errorf("source: can't fetch protected branches deploy keys: %s", err.Error())
+	}
+	tkeys, err := listBranchDeployKeys(target, tarProjectID)
+	if err != nil {
+		return fmt.Errorf("target: can't fetch protected branches deploy keys: %s", err.Error())
+	}
+	branches := make(map[string]*glab.ProtectedBranch)
+	for _, pb := range tpbs {
+		branches[pb.Name] = pb
+	}
+	for _, pb := range pbs {
+		if err := m.migrateProtectedBranch(pb, branches[pb.Name], keys, tkeys); err != nil {
+			return err
+		}
+	}
+
+	fmt.Println("Copying protected tags ...")
+	pts, err := listProtectedTags(source, srcProjectID)
+	if err != nil {
+		return fmt.Errorf("source: can't fetch protected tags: %s", err.Error())
+	}
+	fmt.Printf("Found %d protected tags\n", len(pts))
+	tpts, err := listProtectedTags(target, tarProjectID)
+	if err != nil {
+		return fmt.Errorf("target: can't fetch protected tags: %s", err.Error())
+	}
+	tags := make(map[string]*glab.ProtectedTag)
+	for _, pt := range tpts {
+		tags[pt.Name] = pt
+	}
+	for _, pt := range pts {
+		if err := m.migrateProtectedTag(pt, tags[pt.Name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,283 @@
+package migration
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/gotsunami/gitlab-copy/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	glab "github.com/xanzy/go-gitlab"
+)
+
+func TestMigrateProtectedRefs(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	runs := []struct {
+		name    string                     // Sub-test name
+		config  string                     // YAML config
+		setup   func(src, dst *fakeClient) // Defines any option before calling Migrate()
+		asserts func(err error, src, dst *fakeClient)
+	}{
+		{
+			"Listing source protected branches fails",
+			cfg15,
+			func(src, dst *fakeClient) {
+				src.errors.listProtectedBranches = errors.New("err")
+			},
+			func(err error, src, dst *fakeClient) {
+				assert.Error(err)
+			},
+		},
+		{
+			"Protecting tag fails",
+			cfg15,
+			func(src, dst *fakeClient) {
+				src.protectedTags = []*glab.ProtectedTag{{Name: "v*"}}
+				dst.errors.protectTag = errors.New("err")
+			},
+			func(err error, src, dst *fakeClient) {
+				assert.Error(err)
+			},
+		},
+		{
+			"Branches already protected on target with the same access levels are skipped",
+			cfg15,
+			func(src, dst *fakeClient) {
+				src.protectedBranches = []*glab.ProtectedBranch{
+					{Name: "main"},
+					{Name: "release/*"},
+				}
+				dst.protectedBranches = []*glab.ProtectedBranch{{Name: "main"}}
+			},
+			func(err error, src, dst *fakeClient) {
+				require.NoError(err)
+				if assert.Len(dst.protectedBranches, 2) {
+					assert.Equal("release/*", dst.protectedBranches[1].Name)
+				}
+			},
+		},
+		{
+			"Access levels copied with users and groups remapped",
+			cfg15,
+			func(src, dst *fakeClient) {
+				src.users = []*glab.User{{ID: 1, Username: "alice"}, {ID: 2, Username: "bob"}}
+				dst.users = []*glab.User{{ID: 10, Username: "alice"}}
+				src.groups = []*glab.Group{
+					{ID: 3, FullPath: "source/release"},
+					{ID: 4, FullPath: "other"},
+				}
+				dst.groups = []*glab.Group{{ID: 30, FullPath: "dest/release"}}
+				src.protectedBranches = []*glab.ProtectedBranch{
+					{
+						Name: "main",
+						PushAccessLevels: []*glab.BranchAccessDescription{
+							{AccessLevel: glab.MaintainerPermissions},
+							{UserID: 1},
+							{UserID: 2},
+						},
+						MergeAccessLevels: []*glab.BranchAccessDescription{
+							{AccessLevel: glab.DeveloperPermissions},
+							{GroupID: 3},
+							{GroupID: 4},
+						},
+						UnprotectAccessLevels: []*glab.BranchAccessDescription{
+							{AccessLevel: glab.OwnerPermissions},
+						},
+						AllowForcePush: true,
+					},
+				}
+				src.protectedTags = []*glab.ProtectedTag{
+					{
+						Name: "v*",
+						CreateAccessLevels: []*glab.TagAccessDescription{
+							{AccessLevel: glab.NoPermissions},
+							{UserID: 1},
+						},
+					},
+				}
+			},
+			func(err error, src, dst *fakeClient) {
+				require.NoError(err)
+				require.Len(dst.protectedBranches, 1)
+				pb := dst.protectedBranches[0]
+				assert.True(pb.AllowForcePush)
+				assert.Equal([]*glab.BranchAccessDescription{
+					{AccessLevel: glab.MaintainerPermissions},
+					{UserID: 10},
+				}, pb.PushAccessLevels)
+				assert.Equal([]*glab.BranchAccessDescription{
+					{AccessLevel: glab.DeveloperPermissions},
+					{GroupID: 30},
+				}, pb.MergeAccessLevels)
+				assert.Equal([]*glab.BranchAccessDescription{
+					{AccessLevel: glab.OwnerPermissions},
+				}, pb.UnprotectAccessLevels)
+				require.Len(dst.protectedTags, 1)
+				assert.Equal([]*glab.TagAccessDescription{
+					{AccessLevel: glab.NoPermissions},
+					{UserID: 10},
+				}, dst.protectedTags[0].CreateAccessLevels)
+			},
+		},
+		{
+			"No access granted when no user or group has a match",
+			cfg15,
+			func(src, dst *fakeClient) {
+				src.users = []*glab.User{{ID: 1, Username: "alice"}}
+				src.groups = []*glab.Group{{ID: 3, FullPath: "source/release"}}
+				src.protectedBranches = []*glab.ProtectedBranch{
+					{
+						Name:              "main",
+						PushAccessLevels:  []*glab.BranchAccessDescription{{UserID: 1}},
+						MergeAccessLevels: []*glab.BranchAccessDescription{{GroupID: 3}},
+					},
+				}
+				src.protectedTags = []*glab.ProtectedTag{
+					{
+						Name:               "v*",
+						CreateAccessLevels: []*glab.TagAccessDescription{{UserID: 1}},
+					},
+				}
+			},
+			func(err error, src, dst *fakeClient) {
+				require.NoError(err)
+				require.Len(dst.protectedBranches, 1)
+				pb := dst.protectedBranches[0]
+				assert.Equal([]*glab.BranchAccessDescription{
+					{AccessLevel: glab.NoPermissions},
+				}, pb.PushAccessLevels)
+				assert.Equal([]*glab.BranchAccessDescription{
+					{AccessLevel: glab.NoPermissions},
+				}, pb.MergeAccessLevels)
+				assert.Empty(pb.UnprotectAccessLevels)
+				require.Len(dst.protectedTags, 1)
+				assert.Equal([]*glab.TagAccessDescription{
+					{AccessLevel: glab.NoPermissions},
+				}, dst.protectedTags[0].CreateAccessLevels)
+			},
+		},
+		{
+			"Rules protected on target with other access levels are replaced",
+			cfg15,
+			func(src, dst *fakeClient) {
+				src.protectedBranches = []*glab.ProtectedBranch{
+					{
+						Name:              "main",
+						PushAccessLevels:  []*glab.BranchAccessDescription{{AccessLevel: glab.NoPermissions}},
+						MergeAccessLevels: []*glab.BranchAccessDescription{{AccessLevel: glab.MaintainerPermissions}},
+					},
+				}
+				src.protectedTags = []*glab.ProtectedTag{
+					{
+						Name:               "v*",
+						CreateAccessLevels: []*glab.TagAccessDescription{{AccessLevel: glab.MaintainerPermissions}},
+					},
+				}
+				// Defaults of a new project.
+				dst.protectedBranches = []*glab.ProtectedBranch{
+					{
+						ID:                    1,
+						Name:                  "main",
+						PushAccessLevels:      []*glab.BranchAccessDescription{{AccessLevel: glab.MaintainerPermissions}},
+						MergeAccessLevels:     []*glab.BranchAccessDescription{{AccessLevel: glab.MaintainerPermissions}},
+						UnprotectAccessLevels: []*glab.BranchAccessDescription{{AccessLevel: glab.MaintainerPermissions}},
+					},
+				}
+				dst.protectedTags = []*glab.ProtectedTag{
+					{
+						Name:               "v*",
+						CreateAccessLevels: []*glab.TagAccessDescription{{AccessLevel: glab.MaintainerPermissions}},
+					},
+				}
+				// Would fail if called.
+				dst.errors.unprotectTag = errors.New("err")
+			},
+			func(err error, src, dst *fakeClient) {
+				require.NoError(err)
+				require.Len(dst.protectedBranches, 1)
+				assert.Equal([]*glab.BranchAccessDescription{
+					{AccessLevel: glab.NoPermissions},
+				}, dst.protectedBranches[0].PushAccessLevels)
+				assert.Len(dst.protectedTags, 1)
+			},
+		},
+		{
+			"Deploy keys are not copied as Maintainer roles",
+			cfg15,
+			func(src, dst *fakeClient) {
+				src.protectedBranches = []*glab.ProtectedBranch{
+					{
+						Name: "main",
+						PushAccessLevels: []*glab.BranchAccessDescription{
+							{ID: 1, AccessLevel: glab.NoPermissions},
+							{ID: 2, AccessLevel: glab.MaintainerPermissions},
+						},
+						MergeAccessLevels: []*glab.BranchAccessDescription{
+							{ID: 3, AccessLevel: glab.MaintainerPermissions},
+						},
+					},
+				}
+				src.branchDeployKeys = map[int]int{2: 5, 3: 6}
+			},
+			func(err error, src, dst *fakeClient) {
+				require.NoError(err)
+				require.Len(dst.protectedBranches, 1)
+				pb := dst.protectedBranches[0]
+				assert.Equal([]*glab.BranchAccessDescription{
+					{AccessLevel: glab.NoPermissions},
+				}, pb.PushAccessLevels)
+				assert.Equal([]*glab.BranchAccessDescription{
+					{AccessLevel: glab.NoPermissions},
+				}, pb.MergeAccessLevels)
+			},
+		},
+		{
+			"Protecting branch again fails after unprotecting it",
+			cfg15,
+			func(src, dst *fakeClient) {
+				src.protectedBranches = []*glab.ProtectedBranch{{Name: "main", AllowForcePush: true}}
+				dst.protectedBranches = []*glab.ProtectedBranch{{Name: "main"}}
+				dst.errors.protectBranch = errors.New("err")
+			},
+			func(err error, src, dst *fakeClient) {
+				if assert.Error(err) {
+					assert.Contains(err.Error(), "left unprotected on target")
+				}
+			},
+		},
+		{
+			"Unprotecting branch fails",
+			cfg15,
+			func(src, dst *fakeClient) {
+				src.protectedBranches = []*glab.ProtectedBranch{{Name: "main", AllowForcePush: true}}
+				dst.protectedBranches = []*glab.ProtectedBranch{{Name: "main"}}
+				dst.errors.unprotectBranch = errors.New("err")
+			},
+			func(err error, src, dst *fakeClient) {
+				assert.Error(err)
+			},
+		},
+	}
+	for _, run := range runs {
+		t.Run(run.name, func(t *testing.T) {
+			conf, err := config.Parse(strings.NewReader(run.config))
+			require.NoError(err)
+			// Load the conf.
+			m, err := New(conf)
+			require.NoError(err)
+			_, err = m.SourceProject(m.params.SrcPrj.Name)
+			require.NoError(err)
+			_, err = m.DestProject(m.params.DstPrj.Name)
+			require.NoError(err)
+			// Setup.
+			run.setup(source(m), dest(m))
+			// Run the migration.
+			err = m.Migrate()
+			// Asserts and tear down.
+			run.asserts(err, source(m), dest(m))
+		})
+	}
+}
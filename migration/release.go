@@ -0,0 +1,148 @@
+package migration
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gotsunami/gitlab-copy/gitlab"
+	glab "github.com/xanzy/go-gitlab"
+)
+
+var (
+	errDuplicateRelease = errors.New("Duplicate Release")
+	errMissingTag       = errors.New("Missing Tag")
+)
+
+// tagExists returns true if tag exists in the target repository.
+func (m *Migration) tagExists(tag string) (bool, error) {
+	_, resp, err := m.Endpoint.DstClient.GetTag(m.dstProject.ID, tag)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return false, nil
+		}
+		return false, fmt.Errorf("target: error getting tag '%s': %s", tag, err.Error())
+	}
+	return true, nil
+}
+
+// listReleases returns all releases of project pid, fetching all pages.
+func listReleases(c gitlab.GitLaber, pid int) ([]*glab.Release, error) {
+	all := make([]*glab.Release, 0)
+	opts := &glab.ListReleasesOptions{ListOptions: glab.ListOptions{PerPage: ResultsPerPage, Page: 1}}
+	for {
+		rs, _, err := c.ListReleases(pid, opts)
+		if err != nil {
+			return nil, err
+		}
+		if len(rs) == 0 {
+			break
+		}
+		all = append(all, rs...)
+		opts.Page++
+	}
+	return all, nil
+}
+
+// migrateRelease recreates the source release r on target. Its milestones
+// are created on target when missing. existing holds the tags of the target
+// releases.
+func (m *Migration) migrateRelease(r *glab.Release, existing map[string]bool) error {
+	source := m.Endpoint.SrcClient
+	target := m.Endpoint.DstClient
+
+	srcProjectID := m.srcProject.ID
+	tarProjectID := m.dstProject.ID
+
+	if existing[r.TagName] {
+		return errDuplicateRelease
+	}
+	ok, err := m.tagExists(r.TagName)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errMissingTag
+	}
+
+	miles, _, err := source.ListReleaseMilestones(srcProjectID, r.TagName)
+	if err != nil {
+		return fmt.Errorf("source: can't get release %s milestones: %s", r.TagName, err.Error())
+	}
+	titles := make([]string, 0)
+	for _, mi := range miles {
+		if _, err := m.targetMilestoneID(mi); err != nil {
+			return err
+		}
+		titles = append(titles, mi.Title)
+	}
+
+	links := make([]*glab.ReleaseAssetLinkOptions, 0)
+	for _, l := range r.Assets.Links {
+		l := l
+		links = append(links, &glab.ReleaseAssetLinkOptions{
+			Name:     &l.Name,
+			URL:      &l.URL,
+			LinkType: &l.LinkType,
+		})
+	}
+
+	desc := m.rewriteUploads(r.Description)
+	ropts := &glab.CreateReleaseOptions{
+		Name:        &r.Name,
+		TagName:     &r.TagName,
+		Description: &desc,
+		ReleasedAt:  r.ReleasedAt,
+		Assets:      &glab.ReleaseAssetsOptions{Links: links},
+	}
+	if len(titles) > 0 {
+		ropts.Milestones = &titles
+	}
+	nr, _, err := target.CreateRelease(tarProjectID, ropts)
+	if err != nil {
+		return fmt.Errorf("target: error creating release %s: %s", r.TagName, err.Error())
+	}
+	existing[nr.TagName] = true
+	fmt.Printf("target: created release %s: %s\n", nr.TagName, nr.Name)
+	return nil
+}
+
+// migrateReleases copies the source releases whose tag exists in the target
+// repository. Releases with a missing tag are reported.
+func (m *Migration) migrateReleases() error {
+	fmt.Println("Copying releases ...")
+
+	releases, err := listReleases(m.Endpoint.SrcClient, m.srcProject.ID)
+	if err != nil {
+		return fmt.Errorf("source: can't fetch releases: %s", err.Error())
+	}
+	fmt.Printf("Found %d releases\n", len(releases))
+	trs, err := listReleases(m.Endpoint.DstClient, m.dstProject.ID)
+	if err != nil {
+		return fmt.Errorf("target: can't fetch releases: %s", err.Error())
+	}
+	existing := make(map[string]bool)
+	for _, r := range trs {
+		existing[r.TagName] = true
+	}
+
+	missing := make([]string, 0)
+	for _, r := range releases {
+		if err := m.migrateRelease(r, existing); err != nil {
+			switch err {
+			case errDuplicateRelease:
+				fmt.Printf("target: release %s already exists, skipping...\n", r.TagName)
+				continue
+			case errMissingTag:
+				missing = append(missing, r.TagName)
+				continue
+			}
+			return err
+		}
+	}
+	if len(missing) > 0 {
+		fmt.Printf("target: missing tags, releases not copied: %s\n", strings.Join(missing, ", "))
+	}
+	return nil
+}
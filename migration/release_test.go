@@ -0,0 +1,180 @@
+package migration
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gotsunami/gitlab-copy/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	glab "github.com/xanzy/go-gitlab"
+)
+
+func TestMigrateReleases(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	runs := []struct {
+		name    string                     // Sub-test name
+		config  string                     // YAML config
+		setup   func(src, dst *fakeClient) // Defines any option before calling Migrate()
+		asserts func(err error, src, dst *fakeClient)
+	}{
+		{
+			"Listing source releases fails",
+			cfg8,
+			func(src, dst *fakeClient) {
+				src.errors.listReleases = errors.New("err")
+			},
+			func(err error, src, dst *fakeClient) {
+				assert.Error(err)
+			},
+		},
+		{
+			"Listing target releases fails",
+			cfg8,
+			func(src, dst *fakeClient) {
+				dst.errors.listReleases = errors.New("err")
+			},
+			func(err error, src, dst *fakeClient) {
+				assert.Error(err)
+			},
+		},
+		{
+			"Getting tag fails",
+			cfg8,
+			func(src, dst *fakeClient) {
+				src.releases = makeReleases("v1.0")
+				dst.errors.getTag = errors.New("err")
+			},
+			func(err error, src, dst *fakeClient) {
+				assert.Error(err)
+			},
+		},
+		{
+			"Missing tags are skipped",
+			cfg8,
+			func(src, dst *fakeClient) {
+				src.releases = makeReleases("v1.0", "v1.1", "v2.0")
+				dst.tags = []string{"v1.1"}
+			},
+			func(err error, src, dst *fakeClient) {
+				require.NoError(err)
+				if assert.Len(dst.releases, 1) {
+					assert.Equal("v1.1", dst.releases[0].TagName)
+				}
+			},
+		},
+		{
+			"Existing releases are skipped",
+			cfg8,
+			func(src, dst *fakeClient) {
+				src.releases = makeReleases("v1.0", "v1.1")
+				dst.releases = makeReleases("v1.0")
+				dst.releases[0].Name = "target release"
+				dst.tags = []string{"v1.0", "v1.1"}
+			},
+			func(err error, src, dst *fakeClient) {
+				require.NoError(err)
+				if assert.Len(dst.releases, 2) {
+					assert.Equal("target release", dst.releases[0].Name)
+					assert.Equal("v1.1", dst.releases[1].TagName)
+				}
+			},
+		},
+		{
+			"Release copied with notes, date, links and milestones",
+			cfg8,
+			func(src, dst *fakeClient) {
+				src.releases = makeReleases("v1.0")
+				at := time.Date(2022, 5, 4, 0, 0, 0, 0, time.UTC)
+				src.releases[0].ReleasedAt = &at
+				src.releases[0].Assets.Links = []*glab.ReleaseLink{
+					{Name: "binary", URL: "https://example.com/bin", LinkType: "package"},
+				}
+				src.releaseMilestones = map[string][]*glab.Milestone{
+					"v1.0": makeMilestones("m1", "m2"),
+				}
+				dst.milestones = makeMilestones("m2")
+				dst.tags = []string{"v1.0"}
+			},
+			func(err error, src, dst *fakeClient) {
+				require.NoError(err)
+				if assert.Len(dst.releases, 1) {
+					r := dst.releases[0]
+					assert.Equal("release v1.0", r.Name)
+					assert.Equal("notes of v1.0", r.Description)
+					assert.Equal("2022-05-04", r.ReleasedAt.Format("2006-01-02"))
+					if assert.Len(r.Assets.Links, 1) {
+						assert.Equal("binary", r.Assets.Links[0].Name)
+						assert.Equal("https://example.com/bin", r.Assets.Links[0].URL)
+						assert.Equal(glab.PackageLinkType, r.Assets.Links[0].LinkType)
+					}
+				}
+				// Missing milestone created on target.
+				assert.Len(dst.milestones, 2)
+				if assert.Len(dst.releaseMilestones["v1.0"], 2) {
+					assert.Equal("m1", dst.releaseMilestones["v1.0"][0].Title)
+					assert.Equal("m2", dst.releaseMilestones["v1.0"][1].Title)
+				}
+			},
+		},
+		{
+			"Listing release milestones fails",
+			cfg8,
+			func(src, dst *fakeClient) {
+				src.releases = makeReleases("v1.0")
+				src.errors.listReleaseMilestones = errors.New("err")
+				dst.tags = []string{"v1.0"}
+			},
+			func(err error, src, dst *fakeClient) {
+				assert.Error(err)
+			},
+		},
+		{
+			"Creating release fails",
+			cfg8,
+			func(src, dst *fakeClient) {
+				src.releases = makeReleases("v1.0")
+				dst.errors.createRelease = errors.New("err")
+				dst.tags = []string{"v1.0"}
+			},
+			func(err error, src, dst *fakeClient) {
+				assert.Error(err)
+			},
+		},
+	}
+	for _, run := range runs {
+		t.Run(run.name, func(t *testing.T) {
+			conf, err := config.Parse(strings.NewReader(run.config))
+			require.NoError(err)
+			// Load the conf.
+			m, err := New(conf)
+			require.NoError(err)
+			_, err = m.SourceProject(m.params.SrcPrj.Name)
+			require.NoError(err)
+			_, err = m.DestProject(m.params.DstPrj.Name)
+			require.NoError(err)
+			// Setup.
+			run.setup(source(m), dest(m))
+			// Run the migration.
+			err = m.Migrate()
+			// Asserts and tear down.
+			run.asserts(err, source(m), dest(m))
+		})
+	}
+}
+
+func makeReleases(tags ...string) []*glab.Release {
+	releases := make([]*glab.Release, len(tags))
+	for k, t := range tags {
+		releases[k] = &glab.Release{
+			TagName:     t,
+			Name:        "release " + t,
+			Description: "notes of " + t,
+		}
+	}
+	return releases
+}
@@ -0,0 +1,181 @@
+package migration
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+
+	glab "github.com/xanzy/go-gitlab"
+)
+
+// avatarSetting is the name of the project avatar setting, which is not a
+// plain field since the image has to be copied.
+const avatarSetting = "avatar"
+
+// projectSettings maps the setting names allowed in the config file to the
+// fields they stand for. Each field is named after both a glab.Project field
+// and a glab.EditProjectOptions field of the same (pointer) type.
+var projectSettings = map[string][]string{
+	"description":   {"Description"},
+	"topics":        {"Topics"},
+	"defaultBranch": {"DefaultBranch"},
+	avatarSetting:   nil,
+	"features": {
+		"IssuesAccessLevel",
+		"RepositoryAccessLevel",
+		"MergeRequestsAccessLevel",
+		"ForkingAccessLevel",
+		"WikiAccessLevel",
+		"BuildsAccessLevel",
+		"SnippetsAccessLevel",
+		"PagesAccessLevel",
+		"OperationsAccessLevel",
+		"AnalyticsAccessLevel",
+		"ContainerRegistryAccessLevel",
+		"ReleasesAccessLevel",
+		"LFSEnabled",
+		"PackagesEnabled",
+		"RequestAccessEnabled",
+	},
+	"mergeSettings": {
+		"MergeMethod",
+		"SquashOption",
+		"OnlyAllowMergeIfPipelineSucceeds",
+		"AllowMergeOnSkippedPipeline",
+		"OnlyAllowMergeIfAllDiscussionsAreResolved",
+		"RemoveSourceBranchAfterMerge",
+		"ResolveOutdatedDiffDiscussions",
+		"PrintingMergeRequestLinkEnabled",
+		"MergeCommitTemplate",
+		"SquashCommitTemplate",
+	},
+}
+
+// SettingChange is a project setting whose target value differs from the
+// source one.
+type SettingChange struct {
+	Name string
+	// Current target value and source value
+	From, To string
+	// Field name, empty for the avatar
+	field string
+}
+
+// settingFields returns the fields of the settings names, or an error if one
+// of them is not supported.
+func settingFields(names []string) ([]string, error) {
+	fields := make([]string, 0)
+	for _, name := range names {
+		fs, ok := projectSettings[name]
+		if !ok {
+			valid := make([]string, 0, len(projectSettings))
+			for n := range projectSettings {
+				valid = append(valid, n)
+			}
+			sort.Strings(valid)
+			return nil, fmt.Errorf("unknown project setting '%s', expects one of: %s", name, strings.Join(valid, ", "))
+		}
+		if name == avatarSetting {
+			fs = []string{""}
+		}
+		fields = append(fields, fs...)
+	}
+	return fields, nil
+}
+
+// settingName returns the API name of a glab.Project field.
+func settingName(field string) string {
+	if field == "" {
+		return avatarSetting
+	}
+	f, _ := reflect.TypeOf(glab.Project{}).FieldByName(field)
+	return strings.Split(f.Tag.Get("json"), ",")[0]
+}
+
+// settingValue returns a printable value of the field of project p.
+func settingValue(p *glab.Project, field string) string {
+	if field == "" {
+		u, err := url.Parse(p.AvatarURL)
+		if err != nil || p.AvatarURL == "" {
+			return ""
+		}
+		return path.Base(u.Path)
+	}
+	v := reflect.ValueOf(p).Elem().FieldByName(field)
+	if ss, ok := v.Interface().([]string); ok {
+		return strings.Join(ss, ", ")
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+// SettingsDiff returns the project settings listed in the config file whose
+// value differs between the source and target projects.
+func (m *Migration) SettingsDiff() ([]SettingChange, error) {
+	fields, err := settingFields(m.params.SrcPrj.Settings)
+	if err != nil {
+		return nil, err
+	}
+	changes := make([]SettingChange, 0)
+	for _, f := range fields {
+		from, to := settingValue(m.dstProject, f), settingValue(m.srcProject, f)
+		// An avatar can't be removed that way.
+		if from == to || f == "" && to == "" {
+			continue
+		}
+		changes = append(changes, SettingChange{Name: settingName(f), From: from, To: to, field: f})
+	}
+	return changes, nil
+}
+
+// setSetting sets the field of opts to the source project's value.
+func (m *Migration) setSetting(opts *glab.EditProjectOptions, field string) error {
+	if field == "" {
+		data, _, err := m.Endpoint.SrcClient.DownloadFile(m.srcProject.AvatarURL)
+		if err != nil {
+			return fmt.Errorf("source: can't download avatar: %s", err.Error())
+		}
+		opts.Avatar = &glab.ProjectAvatar{
+			Filename: settingValue(m.srcProject, field),
+			Image:    bytes.NewReader(data),
+		}
+		return nil
+	}
+	v := reflect.ValueOf(m.srcProject).Elem().FieldByName(field)
+	p := reflect.New(v.Type())
+	p.Elem().Set(v)
+	reflect.ValueOf(opts).Elem().FieldByName(field).Set(p)
+	return nil
+}
+
+// migrateSettings applies the source project settings listed in the config
+// file to the target project. Only the settings which differ are sent.
+func (m *Migration) migrateSettings() error {
+	fmt.Println("Copying project settings ...")
+	changes, err := m.SettingsDiff()
+	if err != nil {
+		return err
+	}
+	if len(changes) == 0 {
+		fmt.Println("target: project settings already up to date")
+		return nil
+	}
+	opts := new(glab.EditProjectOptions)
+	for _, c := range changes {
+		if err := m.setSetting(opts, c.field); err != nil {
+			return err
+		}
+	}
+	p, _, err := m.Endpoint.DstClient.EditProject(m.dstProject.ID, opts)
+	if err != nil {
+		return fmt.Errorf("target: error editing project settings: %s", err.Error())
+	}
+	m.dstProject = p
+	for _, c := range changes {
+		fmt.Printf("target: changed %s: %q -> %q\n", c.Name, c.From, c.To)
+	}
+	return nil
+}
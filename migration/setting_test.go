@@ -0,0 +1,154 @@
+package migration
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gotsunami/gitlab-copy/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	glab "github.com/xanzy/go-gitlab"
+)
+
+func TestProjectSettingsFields(t *testing.T) {
+	pt := reflect.TypeOf(glab.Project{})
+	ot := reflect.TypeOf(glab.EditProjectOptions{})
+	for name, fields := range projectSettings {
+		for _, field := range fields {
+			pf, ok := pt.FieldByName(field)
+			if !ok {
+				t.Errorf("%s: no project field %s", name, field)
+				continue
+			}
+			of, ok := ot.FieldByName(field)
+			if !ok {
+				t.Errorf("%s: no edit option %s", name, field)
+				continue
+			}
+			if of.Type != reflect.PtrTo(pf.Type) {
+				t.Errorf("%s: %s types mismatch: %s, %s", name, field, pf.Type, of.Type)
+			}
+		}
+	}
+}
+
+func TestMigrateSettings(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	runs := []struct {
+		name    string                     // Sub-test name
+		setup   func(src, dst *fakeClient) // Defines any option before calling Migrate()
+		asserts func(err error, src, dst *fakeClient)
+	}{
+		{
+			"Settings already up to date",
+			func(src, dst *fakeClient) {
+				src.project = &glab.Project{Description: "desc", MergeMethod: glab.FastForwardMerge}
+				dst.project = &glab.Project{Description: "desc", MergeMethod: glab.FastForwardMerge}
+			},
+			func(err error, src, dst *fakeClient) {
+				require.NoError(err)
+				assert.Nil(dst.editProjectOptions)
+			},
+		},
+		{
+			"Only differing settings are applied",
+			func(src, dst *fakeClient) {
+				src.project = &glab.Project{
+					Description:                      "new desc",
+					AvatarURL:                        "https://gitlab.mydomain.com/uploads/project/avatar/1/logo.png",
+					MergeMethod:                      glab.FastForwardMerge,
+					OnlyAllowMergeIfPipelineSucceeds: true,
+					RemoveSourceBranchAfterMerge:     true,
+				}
+				src.files = map[string][]byte{src.project.AvatarURL: []byte("png")}
+				dst.project = &glab.Project{
+					Description:                  "old desc",
+					MergeMethod:                  glab.NoFastForwardMerge,
+					RemoveSourceBranchAfterMerge: true,
+				}
+			},
+			func(err error, src, dst *fakeClient) {
+				require.NoError(err)
+				opts := dst.editProjectOptions
+				require.NotNil(opts)
+				if assert.NotNil(opts.Description) {
+					assert.Equal("new desc", *opts.Description)
+				}
+				if assert.NotNil(opts.MergeMethod) {
+					assert.Equal(glab.FastForwardMerge, *opts.MergeMethod)
+				}
+				if assert.NotNil(opts.OnlyAllowMergeIfPipelineSucceeds) {
+					assert.True(*opts.OnlyAllowMergeIfPipelineSucceeds)
+				}
+				assert.Nil(opts.RemoveSourceBranchAfterMerge)
+				assert.Nil(opts.Topics)
+				if assert.NotNil(opts.Avatar) {
+					assert.Equal("logo.png", opts.Avatar.Filename)
+					data, _ := io.ReadAll(opts.Avatar.Image)
+					assert.Equal("png", string(data))
+				}
+			},
+		},
+		{
+			"Editing project fails",
+			func(src, dst *fakeClient) {
+				src.project = &glab.Project{Description: "new desc"}
+				dst.errors.editProject = errors.New("err")
+			},
+			func(err error, src, dst *fakeClient) {
+				assert.Error(err)
+			},
+		},
+	}
+	for _, run := range runs {
+		t.Run(run.name, func(t *testing.T) {
+			conf, err := config.Parse(strings.NewReader(cfg14))
+			require.NoError(err)
+			// Load the conf.
+			m, err := New(conf)
+			require.NoError(err)
+			// Setup.
+			run.setup(source(m), dest(m))
+			// Run the migration.
+			err = m.Migrate()
+			// Asserts and tear down.
+			run.asserts(err, source(m), dest(m))
+		})
+	}
+}
+
+func TestSettingsDiff(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	conf, err := config.Parse(strings.NewReader(cfg14))
+	require.NoError(err)
+	m, err := New(conf)
+	require.NoError(err)
+	source(m).project = &glab.Project{Description: "new desc", SquashOption: glab.SquashOptionAlways}
+	dest(m).project = &glab.Project{Description: "old desc", SquashOption: glab.SquashOptionDefaultOff}
+	_, err = m.SourceProject(m.params.SrcPrj.Name)
+	require.NoError(err)
+	_, err = m.DestProject(m.params.DstPrj.Name)
+	require.NoError(err)
+
+	changes, err := m.SettingsDiff()
+	require.NoError(err)
+	if assert.Len(changes, 2) {
+		assert.Equal("description", changes[0].Name)
+		assert.Equal("old desc", changes[0].From)
+		assert.Equal("new desc", changes[0].To)
+		assert.Equal("squash_option", changes[1].Name)
+		assert.Equal("default_off", changes[1].From)
+		assert.Equal("always", changes[1].To)
+	}
+
+	m.params.SrcPrj.Settings = []string{"description", "colors"}
+	_, err = m.SettingsDiff()
+	assert.Error(err)
+}
@@ -0,0 +1,124 @@
+package migration
+
+import (
+	"errors"
+	"fmt"
+
+	glab "github.com/xanzy/go-gitlab"
+)
+
+var errDuplicateSnippet = errors.New("Duplicate Snippet")
+
+// snippetFiles downloads the files of the source snippet s.
+func (m *Migration) snippetFiles(s *glab.Snippet) ([]*glab.CreateSnippetFileOptions, error) {
+	source := m.Endpoint.SrcClient
+
+	files := make([]*glab.CreateSnippetFileOptions, 0)
+	for _, f := range s.Files {
+		data, _, err := source.DownloadFile(f.RawURL)
+		if err != nil {
+			return nil, fmt.Errorf("source: error downloading snippet $%d file %s: %s", s.ID, f.Path, err.Error())
+		}
+		p, content := f.Path, string(data)
+		files = append(files, &glab.CreateSnippetFileOptions{FilePath: &p, Content: &content})
+	}
+	if len(files) == 0 && s.RawURL != "" {
+		// GitLab versions without multiple files per snippet.
+		data, _, err := source.DownloadFile(s.RawURL)
+		if err != nil {
+			return nil, fmt.Errorf("source: error downloading snippet $%d content: %s", s.ID, err.Error())
+		}
+		p, content := s.FileName, string(data)
+		files = append(files, &glab.CreateSnippetFileOptions{FilePath: &p, Content: &content})
+	}
+	return files, nil
+}
+
+// migrateSnippet copies the source snippet s to the target project, along with
+// its notes. existing holds the titles of the target snippets.
+func (m *Migration) migrateSnippet(s *glab.Snippet, existing map[string]bool) error {
+	target := m.Endpoint.DstClient
+	tarProjectID := m.dstProject.ID
+
+	if existing[s.Title] {
+		return errDuplicateSnippet
+	}
+	files, err := m.snippetFiles(s)
+	if err != nil {
+		return err
+	}
+	desc := m.rewriteUploads(s.Description)
+	visibility := glab.VisibilityValue(s.Visibility)
+	sopts := &glab.CreateProjectSnippetOptions{
+		Title:       &s.Title,
+		Description: &desc,
+		Visibility:  &visibility,
+		Files:       &files,
+	}
+	ns, _, err := target.CreateSnippet(tarProjectID, sopts)
+	if err != nil {
+		return fmt.Errorf("target: error creating snippet '%s': %s", s.Title, err.Error())
+	}
+	existing[ns.Title] = true
+
+	ds, err := m.snippetDiscussions(s.ID)
+	if err != nil {
+		return err
+	}
+	if _, err := m.copyDiscussions(&snippetThreads{pid: tarProjectID, id: ns.ID}, ds); err != nil {
+		return err
+	}
+
+	fmt.Printf("target: created snippet $%d: %s\n", ns.ID, ns.Title)
+	return nil
+}
+
+// migrateSnippets copies all source snippets which don't exist on target yet
+// (by title).
+func (m *Migration) migrateSnippets() error {
+	source := m.Endpoint.SrcClient
+	target := m.Endpoint.DstClient
+
+	srcProjectID := m.srcProject.ID
+	tarProjectID := m.dstProject.ID
+
+	fmt.Println("Copying snippets ...")
+
+	existing := make(map[string]bool)
+	opts := &glab.ListProjectSnippetsOptions{PerPage: ResultsPerPage, Page: 1}
+	for {
+		ts, _, err := target.ListSnippets(tarProjectID, opts)
+		if err != nil {
+			return fmt.Errorf("target: can't fetch snippets: %s", err.Error())
+		}
+		if len(ts) == 0 {
+			break
+		}
+		for _, s := range ts {
+			existing[s.Title] = true
+		}
+		opts.Page++
+	}
+
+	opts = &glab.ListProjectSnippetsOptions{PerPage: ResultsPerPage, Page: 1}
+	for {
+		snippets, _, err := source.ListSnippets(srcProjectID, opts)
+		if err != nil {
+			return fmt.Errorf("source: can't fetch snippets: %s", err.Error())
+		}
+		if len(snippets) == 0 {
+			break
+		}
+		for _, s := range snippets {
+			if err := m.migrateSnippet(s, existing); err != nil {
+				if err == errDuplicateSnippet {
+					fmt.Printf("target: snippet %d already exists, skipping...\n", s.ID)
+					continue
+				}
+				return err
+			}
+		}
+		opts.Page++
+	}
+	return nil
+}
@@ -0,0 +1,189 @@
+package migration
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/gotsunami/gitlab-copy/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	glab "github.com/xanzy/go-gitlab"
+)
+
+func TestMigrateSnippets(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	runs := []struct {
+		name    string                                   // Sub-test name
+		config  string                                   // YAML config
+		setup   func(m *Migration, src, dst *fakeClient) // Defines any option before calling Migrate()
+		asserts func(err error, src, dst *fakeClient)
+	}{
+		{
+			"Listing source snippets fails",
+			cfg7,
+			func(m *Migration, src, dst *fakeClient) {
+				src.errors.listSnippets = errors.New("err")
+			},
+			func(err error, src, dst *fakeClient) {
+				assert.Error(err)
+			},
+		},
+		{
+			"Listing target snippets fails",
+			cfg7,
+			func(m *Migration, src, dst *fakeClient) {
+				dst.errors.listSnippets = errors.New("err")
+			},
+			func(err error, src, dst *fakeClient) {
+				assert.Error(err)
+			},
+		},
+		{
+			"Downloading snippet file fails",
+			cfg7,
+			func(m *Migration, src, dst *fakeClient) {
+				src.snippets = makeSnippets(src, "runbook")
+				src.errors.downloadFile = errors.New("err")
+			},
+			func(err error, src, dst *fakeClient) {
+				assert.Error(err)
+				assert.Empty(dst.snippets)
+			},
+		},
+		{
+			"Creating snippet fails",
+			cfg7,
+			func(m *Migration, src, dst *fakeClient) {
+				src.snippets = makeSnippets(src, "runbook")
+				dst.errors.createSnippet = errors.New("err")
+			},
+			func(err error, src, dst *fakeClient) {
+				assert.Error(err)
+			},
+		},
+		{
+			"Snippets copied with their files, existing titles skipped",
+			cfg7,
+			func(m *Migration, src, dst *fakeClient) {
+				src.snippets = makeSnippets(src, "runbook", "script")
+				src.snippets[1].Visibility = "internal"
+				dst.snippets = makeSnippets(dst, "runbook")
+			},
+			func(err error, src, dst *fakeClient) {
+				require.NoError(err)
+				if assert.Len(dst.snippets, 2) {
+					s := dst.snippets[1]
+					assert.Equal("script", s.Title)
+					assert.Equal("description of script", s.Description)
+					assert.Equal("internal", s.Visibility)
+					if assert.Len(s.Files, 2) {
+						assert.Equal("script.sh", s.Files[0].Path)
+						assert.Equal([]byte("content of script.sh"), dst.files[s.Files[0].RawURL])
+						assert.Equal("README.md", s.Files[1].Path)
+					}
+				}
+			},
+		},
+		{
+			"Single file snippet",
+			cfg7,
+			func(m *Migration, src, dst *fakeClient) {
+				src.snippets = []*glab.Snippet{{ID: 1, Title: "old", FileName: "old.txt", RawURL: "/snippets/1/raw"}}
+				src.files = map[string][]byte{"/snippets/1/raw": []byte("old content")}
+			},
+			func(err error, src, dst *fakeClient) {
+				require.NoError(err)
+				if assert.Len(dst.snippets, 1) && assert.Len(dst.snippets[0].Files, 1) {
+					f := dst.snippets[0].Files[0]
+					assert.Equal("old.txt", f.Path)
+					assert.Equal([]byte("old content"), dst.files[f.RawURL])
+				}
+			},
+		},
+		{
+			"Snippet notes copied, preserving ownership",
+			cfg7,
+			func(m *Migration, src, dst *fakeClient) {
+				src.snippets = makeSnippets(src, "runbook")
+				notes := makeNotes("n1", "n2", "n3")
+				notes[0].Body = "first"
+				notes[1].Body = "reply"
+				notes[2].Body = "single"
+				notes[2].Author.Username = "alice"
+				src.discussions = append([]*glab.Discussion{makeThread(notes[:2]...)}, makeDiscussions(notes[2])...)
+				m.toUsers["alice"] = new(fakeClient)
+			},
+			func(err error, src, dst *fakeClient) {
+				require.NoError(err)
+				if assert.Len(dst.discussions, 1) && assert.Len(dst.discussions[0].Notes, 2) {
+					assert.Contains(dst.discussions[0].Notes[0].Body, "@me wrote on")
+					assert.Contains(dst.discussions[0].Notes[0].Body, "first")
+					assert.Contains(dst.discussions[0].Notes[1].Body, "reply")
+				}
+				// Written with alice's token.
+				assert.Empty(dst.snippetNotes)
+			},
+		},
+		{
+			"Creating snippet note fails",
+			cfg7,
+			func(m *Migration, src, dst *fakeClient) {
+				src.snippets = makeSnippets(src, "runbook")
+				src.discussions = makeDiscussions(makeNotes("n1")...)
+				dst.errors.createSnippetNote = errors.New("err")
+			},
+			func(err error, src, dst *fakeClient) {
+				assert.Error(err)
+			},
+		},
+	}
+	for _, run := range runs {
+		t.Run(run.name, func(t *testing.T) {
+			conf, err := config.Parse(strings.NewReader(run.config))
+			require.NoError(err)
+			// Load the conf.
+			m, err := New(conf)
+			require.NoError(err)
+			_, err = m.SourceProject(m.params.SrcPrj.Name)
+			require.NoError(err)
+			_, err = m.DestProject(m.params.DstPrj.Name)
+			require.NoError(err)
+			// Setup.
+			run.setup(m, source(m), dest(m))
+			// Run the migration.
+			err = m.Migrate()
+			// Asserts and tear down.
+			run.asserts(err, source(m), dest(m))
+		})
+	}
+}
+
+// makeSnippets returns snippets with two files each, whose content is stored
+// in c.
+func makeSnippets(c *fakeClient, titles ...string) []*glab.Snippet {
+	snippets := make([]*glab.Snippet, len(titles))
+	for k, t := range titles {
+		files := []string{t + ".sh", "README.md"}
+		snippets[k] = &glab.Snippet{
+			ID:          k,
+			Title:       t,
+			Description: "description of " + t,
+			Visibility:  "private",
+		}
+		for _, f := range files {
+			u := "/snippets/" + t + "/raw/main/" + f
+			snippets[k].Files = append(snippets[k].Files, struct {
+				Path   string `json:"path"`
+				RawURL string `json:"raw_url"`
+			}{f, u})
+			if c.files == nil {
+				c.files = make(map[string][]byte)
+			}
+			c.files[u] = []byte("content of " + f)
+		}
+	}
+	return snippets
+}
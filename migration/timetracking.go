@@ -0,0 +1,167 @@
+package migration
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+
+	glab "github.com/xanzy/go-gitlab"
+)
+
+// GitLab's time units, in seconds: a day is 8 hours, a week 5 days and a
+// month 4 weeks.
+var timeUnits = map[string]int{
+	"mo": 4 * 5 * 8 * 3600,
+	"w":  5 * 8 * 3600,
+	"d":  8 * 3600,
+	"h":  3600,
+	"m":  60,
+	"s":  1,
+}
+
+var (
+	// durationRE matches the parts of a human readable duration, like 1w 2d 3h.
+	durationRE = regexp.MustCompile(`(\d+)\s*(mo|w|d|h|m|s)`)
+	// spentRE matches the system notes written when time is spent on an issue.
+	spentRE = regexp.MustCompile(`^(added|subtracted) (.+?) of time spent`)
+	// resetRE matches the system notes written when time spent is reset.
+	resetRE = regexp.MustCompile(`^removed time spent`)
+)
+
+// timeSpent is a time spent entry, parsed from a system note.
+type timeSpent struct {
+	username string
+	seconds  int
+}
+
+// parseDuration returns the number of seconds of a GitLab human readable
+// duration, like 1h 30m.
+func parseDuration(d string) (int, error) {
+	parts := durationRE.FindAllStringSubmatch(d, -1)
+	if len(parts) == 0 {
+		return 0, fmt.Errorf("invalid duration '%s'", d)
+	}
+	secs := 0
+	for _, p := range parts {
+		n, err := strconv.Atoi(p[1])
+		if err != nil {
+			return 0, err
+		}
+		secs += n * timeUnits[p[2]]
+	}
+	return secs, nil
+}
+
+// formatDuration returns secs as a duration GitLab can parse, in hours at
+// most since longer units depend on the instance's settings.
+func formatDuration(secs int) string {
+	sign := ""
+	if secs < 0 {
+		sign = "-"
+		secs = -secs
+	}
+	d := ""
+	for _, u := range []string{"h", "m", "s"} {
+		if n := secs / timeUnits[u]; n > 0 {
+			d += fmt.Sprintf("%d%s", n, u)
+			secs -= n * timeUnits[u]
+		}
+	}
+	if d == "" {
+		d = "0s"
+	}
+	return sign + d
+}
+
+// parseTimeSpent returns the time spent entries found in the system notes of
+// ds, oldest first. Entries prior to a reset of the time spent are dropped.
+func parseTimeSpent(ds []*glab.Discussion) []*timeSpent {
+	entries := make([]*timeSpent, 0)
+	for _, d := range ds {
+		for _, n := range d.Notes {
+			if !n.System {
+				continue
+			}
+			if resetRE.MatchString(n.Body) {
+				entries = entries[:0]
+				continue
+			}
+			sub := spentRE.FindStringSubmatch(n.Body)
+			if sub == nil {
+				continue
+			}
+			secs, err := parseDuration(sub[2])
+			if err != nil {
+				continue
+			}
+			if sub[1] == "subtracted" {
+				secs = -secs
+			}
+			entries = append(entries, &timeSpent{username: n.Author.Username, seconds: secs})
+		}
+	}
+	return entries
+}
+
+// copyTimeStats copies the time estimate and the time spent of the source
+// issue to the target issue iid. Time spent is attributed to its users, based
+// on the system notes of ds, when their token is available. If the system
+// notes don't account for the total time spent, the total is copied instead.
+func (m *Migration) copyTimeStats(issue *glab.Issue, iid int, ds []*glab.Discussion) error {
+	if issue.TimeStats == nil {
+		return nil
+	}
+	target := m.Endpoint.DstClient
+	tarProjectID := m.dstProject.ID
+
+	if issue.TimeStats.TimeEstimate > 0 {
+		d := formatDuration(issue.TimeStats.TimeEstimate)
+		_, _, err := target.SetTimeEstimate(tarProjectID, iid, &glab.SetTimeEstimateOptions{Duration: &d})
+		if err != nil {
+			return fmt.Errorf("target: error setting time estimate of issue #%d: %s", iid, err.Error())
+		}
+	}
+	if issue.TimeStats.TotalTimeSpent == 0 {
+		return nil
+	}
+
+	entries := parseTimeSpent(ds)
+	total := 0
+	for _, e := range entries {
+		total += e.seconds
+	}
+	if total != issue.TimeStats.TotalTimeSpent {
+		fmt.Printf("target: time spent on issue #%d can't be attributed to users, copying the total\n", iid)
+		entries = []*timeSpent{{seconds: issue.TimeStats.TotalTimeSpent}}
+	} else if !m.params.SrcPrj.TimeTrackingDetails {
+		// Sum up entries per user.
+		perUser := make(map[string]int)
+		for _, e := range entries {
+			perUser[e.username] += e.seconds
+		}
+		users := make([]string, 0, len(perUser))
+		for u := range perUser {
+			users = append(users, u)
+		}
+		sort.Strings(users)
+		entries = entries[:0]
+		for _, u := range users {
+			if perUser[u] != 0 {
+				entries = append(entries, &timeSpent{username: u, seconds: perUser[u]})
+			}
+		}
+	}
+
+	for _, e := range entries {
+		c := target
+		if uc, ok := m.toUsers[e.username]; ok {
+			c = uc
+		}
+		d := formatDuration(e.seconds)
+		if _, _, err := c.AddSpentTime(tarProjectID, iid, &glab.AddSpentTimeOptions{Duration: &d}); err != nil {
+			return fmt.Errorf("target: error adding time spent to issue #%d: %s", iid, err.Error())
+		}
+	}
+	return nil
+}
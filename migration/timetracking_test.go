@@ -0,0 +1,182 @@
+package migration
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/gotsunami/gitlab-copy/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	glab "github.com/xanzy/go-gitlab"
+)
+
+func TestParseDuration(t *testing.T) {
+	assert := assert.New(t)
+
+	set := []struct {
+		duration   string
+		secs       int
+		shouldFail bool
+	}{
+		{"30m", 1800, false},
+		{"1h 30m", 5400, false},
+		{"1d 2h", 10 * 3600, false},
+		{"1w", 40 * 3600, false},
+		{"1mo 1s", 160*3600 + 1, false},
+		{"nothing", 0, true},
+	}
+	for _, d := range set {
+		secs, err := parseDuration(d.duration)
+		if d.shouldFail {
+			assert.Error(err)
+			continue
+		}
+		assert.NoError(err)
+		assert.Equal(d.secs, secs, d.duration)
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("0s", formatDuration(0))
+	assert.Equal("1h30m", formatDuration(5400))
+	assert.Equal("10h", formatDuration(10*3600))
+	assert.Equal("-2m5s", formatDuration(-125))
+}
+
+func TestCopyTimeStats(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	runs := []struct {
+		name    string
+		details bool
+		stats   *glab.TimeStats
+		notes   []string // System notes, as "username:body"
+		asserts func(err error, dst, alice *fakeClient)
+	}{
+		{
+			"No time stats",
+			false,
+			nil,
+			nil,
+			func(err error, dst, alice *fakeClient) {
+				assert.NoError(err)
+				assert.Empty(dst.timeEstimate)
+				assert.Empty(dst.spentTime)
+			},
+		},
+		{
+			"Estimate only",
+			false,
+			&glab.TimeStats{TimeEstimate: 3 * 3600},
+			nil,
+			func(err error, dst, alice *fakeClient) {
+				assert.NoError(err)
+				assert.Equal("3h", dst.timeEstimate)
+				assert.Empty(dst.spentTime)
+			},
+		},
+		{
+			"Time spent without system notes",
+			false,
+			&glab.TimeStats{TotalTimeSpent: 5400},
+			nil,
+			func(err error, dst, alice *fakeClient) {
+				assert.NoError(err)
+				assert.Equal([]string{"1h30m"}, dst.spentTime)
+			},
+		},
+		{
+			"Time spent summed up per user",
+			false,
+			&glab.TimeStats{TotalTimeSpent: 2 * 3600},
+			[]string{
+				"bob:added 1d of time spent",
+				"alice:added 2h of time spent at 2023-01-02",
+				"bob:removed time spent",
+				"alice:added 1h 30m of time spent",
+				"bob:added 1h of time spent",
+				"alice:subtracted 30m of time spent",
+				"alice:changed the description",
+			},
+			func(err error, dst, alice *fakeClient) {
+				assert.NoError(err)
+				assert.Equal([]string{"1h"}, alice.spentTime)
+				assert.Equal([]string{"1h"}, dst.spentTime)
+			},
+		},
+		{
+			"Time spent with details",
+			true,
+			&glab.TimeStats{TotalTimeSpent: 3 * 3600},
+			[]string{
+				"alice:added 2h of time spent",
+				"alice:subtracted 30m of time spent",
+				"bob:added 1h 30m of time spent",
+			},
+			func(err error, dst, alice *fakeClient) {
+				assert.NoError(err)
+				assert.Equal([]string{"2h", "-30m"}, alice.spentTime)
+				assert.Equal([]string{"1h30m"}, dst.spentTime)
+			},
+		},
+		{
+			"System notes don't match the total",
+			true,
+			&glab.TimeStats{TotalTimeSpent: 3 * 3600},
+			[]string{"alice:added 2h of time spent"},
+			func(err error, dst, alice *fakeClient) {
+				assert.NoError(err)
+				assert.Empty(alice.spentTime)
+				assert.Equal([]string{"3h"}, dst.spentTime)
+			},
+		},
+	}
+	for _, run := range runs {
+		t.Run(run.name, func(t *testing.T) {
+			conf, err := config.Parse(strings.NewReader(cfg2))
+			require.NoError(err)
+			conf.SrcPrj.TimeTrackingDetails = run.details
+			m, err := New(conf)
+			require.NoError(err)
+			_, err = m.SourceProject(m.params.SrcPrj.Name)
+			require.NoError(err)
+			_, err = m.DestProject(m.params.DstPrj.Name)
+			require.NoError(err)
+			alice := new(fakeClient)
+			m.toUsers["alice"] = alice
+			notes := make([]*glab.Note, len(run.notes))
+			for k, n := range run.notes {
+				parts := strings.SplitN(n, ":", 2)
+				notes[k] = &glab.Note{Body: parts[1], System: true}
+				notes[k].Author.Username = parts[0]
+			}
+			issue := &glab.Issue{TimeStats: run.stats}
+			err = m.copyTimeStats(issue, 1, makeDiscussions(notes...))
+			run.asserts(err, dest(m), alice)
+		})
+	}
+}
+
+func TestCopyTimeStatsErrors(t *testing.T) {
+	require := require.New(t)
+
+	conf, err := config.Parse(strings.NewReader(cfg2))
+	require.NoError(err)
+	m, err := New(conf)
+	require.NoError(err)
+	_, err = m.SourceProject(m.params.SrcPrj.Name)
+	require.NoError(err)
+	_, err = m.DestProject(m.params.DstPrj.Name)
+	require.NoError(err)
+
+	issue := &glab.Issue{TimeStats: &glab.TimeStats{TimeEstimate: 60, TotalTimeSpent: 60}}
+	dest(m).errors.setTimeEstimate = errors.New("err")
+	require.Error(m.copyTimeStats(issue, 1, nil))
+	dest(m).errors.setTimeEstimate = nil
+	dest(m).errors.addSpentTime = errors.New("err")
+	require.Error(m.copyTimeStats(issue, 1, nil))
+}
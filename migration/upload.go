@@ -0,0 +1,66 @@
+package migration
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// uploadRE matches the links to project uploads in markdown, like
+// /uploads/66dbcd21ec5d24ed6ea225176098d52b/shot.png, possibly preceded by
+// the project's URL.
+var uploadRE = regexp.MustCompile(`(\S*)(/uploads/[0-9a-f]{32}/[^\s)"'\]]+)`)
+
+// rewriteUploads copies the source project uploads referenced in text to the
+// target project and returns text with the links pointing to the new uploads.
+// Links to uploads that can't be copied are left untouched.
+func (m *Migration) rewriteUploads(text string) string {
+	return uploadRE.ReplaceAllStringFunc(text, func(match string) string {
+		sub := uploadRE.FindStringSubmatch(match)
+		prefix, upload := sub[1], sub[2]
+		if base := strings.TrimSuffix(m.srcProject.WebURL, "/"); base != "" && strings.HasSuffix(prefix, base) {
+			// Absolute link to a source upload.
+			prefix = strings.TrimSuffix(prefix, base)
+		} else if prefix != "" && !strings.ContainsAny(prefix[len(prefix)-1:], `("`) {
+			// Upload of another project, leave it as is.
+			return match
+		}
+		nu, err := m.copyUpload(upload)
+		if err != nil {
+			fmt.Printf("warning: can't copy upload %s: %s\n", upload, err.Error())
+			return match
+		}
+		return prefix + nu
+	})
+}
+
+// copyUpload copies a single source upload to the target project and returns
+// its new relative URL. Uploads are copied once per migration. They are
+// downloaded through the API, falling back to the project's web path on
+// GitLab versions without the uploads API.
+func (m *Migration) copyUpload(upload string) (string, error) {
+	if nu, ok := m.uploads[upload]; ok {
+		return nu, nil
+	}
+	name := path.Base(upload)
+	if n, err := url.PathUnescape(name); err == nil {
+		name = n
+	}
+	secret := path.Base(path.Dir(upload))
+	data, _, err := m.Endpoint.SrcClient.DownloadUpload(m.srcProject.ID, secret, name)
+	if err != nil {
+		src := strings.TrimSuffix(m.srcProject.WebURL, "/") + upload
+		if data, _, err = m.Endpoint.SrcClient.DownloadFile(src); err != nil {
+			return "", fmt.Errorf("source: error downloading %s: %s", src, err.Error())
+		}
+	}
+	f, _, err := m.Endpoint.DstClient.UploadFile(m.dstProject.ID, bytes.NewReader(data), name)
+	if err != nil {
+		return "", fmt.Errorf("target: error uploading %s: %s", name, err.Error())
+	}
+	m.uploads[upload] = f.URL
+	return f.URL, nil
+}
@@ -0,0 +1,138 @@
+package migration
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/gotsunami/gitlab-copy/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	upload1 = "/uploads/66dbcd21ec5d24ed6ea225176098d52b/shot.png"
+	upload2 = "/uploads/0123456789abcdef0123456789abcdef/log%20file.txt"
+)
+
+func TestRewriteUploads(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	runs := []struct {
+		name    string
+		text    string
+		setup   func(src, dst *fakeClient)
+		asserts func(text string, src, dst *fakeClient)
+	}{
+		{
+			"No upload",
+			"Some text, no link",
+			func(src, dst *fakeClient) {},
+			func(text string, src, dst *fakeClient) {
+				assert.Equal("Some text, no link", text)
+				assert.Empty(dst.files)
+			},
+		},
+		{
+			"Relative links",
+			"![shot](" + upload1 + ") and [log](" + upload2 + ") and ![again](" + upload1 + ")",
+			func(src, dst *fakeClient) {
+				src.files = map[string][]byte{upload1: []byte("png"), upload2: []byte("log")}
+			},
+			func(text string, src, dst *fakeClient) {
+				if assert.Len(dst.files, 2) {
+					assert.Equal([]byte("png"), dst.files["/uploads/00000000000000000000000000000000/shot.png"])
+					assert.Equal([]byte("log"), dst.files["/uploads/00000000000000000000000000000001/log file.txt"])
+				}
+				assert.Equal("![shot](/uploads/00000000000000000000000000000000/shot.png) and "+
+					"[log](/uploads/00000000000000000000000000000001/log file.txt) and "+
+					"![again](/uploads/00000000000000000000000000000000/shot.png)", text)
+			},
+		},
+		{
+			"Absolute link to source project",
+			"[shot](https://gitlab.mydomain.com/source/project" + upload1 + ")",
+			func(src, dst *fakeClient) {
+				src.files = map[string][]byte{"https://gitlab.mydomain.com/source/project" + upload1: []byte("png")}
+			},
+			func(text string, src, dst *fakeClient) {
+				assert.Len(dst.files, 1)
+				assert.Equal("[shot](/uploads/00000000000000000000000000000000/shot.png)", text)
+			},
+		},
+		{
+			"Link to another project upload",
+			"[shot](https://gitlab.mydomain.com/other/project" + upload1 + ")",
+			func(src, dst *fakeClient) {},
+			func(text string, src, dst *fakeClient) {
+				assert.Empty(dst.files)
+				assert.Equal("[shot](https://gitlab.mydomain.com/other/project"+upload1+")", text)
+			},
+		},
+		{
+			"Downloaded through the API when the web path is refused",
+			"![shot](" + upload1 + ")",
+			func(src, dst *fakeClient) {
+				src.files = map[string][]byte{upload1: []byte("png")}
+				src.errors.downloadFile = errors.New("err")
+			},
+			func(text string, src, dst *fakeClient) {
+				assert.Len(dst.files, 1)
+				assert.Equal("![shot](/uploads/00000000000000000000000000000000/shot.png)", text)
+			},
+		},
+		{
+			"Uploads API unavailable, downloaded from the web path",
+			"![shot](" + upload1 + ")",
+			func(src, dst *fakeClient) {
+				src.files = map[string][]byte{upload1: []byte("png")}
+				src.errors.downloadUpload = errors.New("err")
+			},
+			func(text string, src, dst *fakeClient) {
+				assert.Len(dst.files, 1)
+				assert.Equal("![shot](/uploads/00000000000000000000000000000000/shot.png)", text)
+			},
+		},
+		{
+			"Download fails, link left untouched",
+			"![shot](" + upload1 + ")",
+			func(src, dst *fakeClient) {
+				src.errors.downloadFile = errors.New("err")
+			},
+			func(text string, src, dst *fakeClient) {
+				assert.Empty(dst.files)
+				assert.Equal("![shot]("+upload1+")", text)
+			},
+		},
+		{
+			"Upload fails, link left untouched",
+			"![shot](" + upload1 + ")",
+			func(src, dst *fakeClient) {
+				src.files = map[string][]byte{upload1: []byte("png")}
+				dst.errors.uploadFile = errors.New("err")
+			},
+			func(text string, src, dst *fakeClient) {
+				assert.Equal("![shot]("+upload1+")", text)
+			},
+		},
+	}
+	for _, run := range runs {
+		t.Run(run.name, func(t *testing.T) {
+			conf, err := config.Parse(strings.NewReader(cfg2))
+			require.NoError(err)
+			m, err := New(conf)
+			require.NoError(err)
+			_, err = m.SourceProject(m.params.SrcPrj.Name)
+			require.NoError(err)
+			_, err = m.DestProject(m.params.DstPrj.Name)
+			require.NoError(err)
+			if strings.Contains(run.text, "https://") {
+				m.srcProject.WebURL = "https://gitlab.mydomain.com/source/project"
+			}
+			run.setup(source(m), dest(m))
+			text := m.rewriteUploads(run.text)
+			run.asserts(text, source(m), dest(m))
+		})
+	}
+}
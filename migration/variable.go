@@ -0,0 +1,79 @@
+package migration
+
+import (
+	"fmt"
+
+	"github.com/gotsunami/gitlab-copy/gitlab"
+	glab "github.com/xanzy/go-gitlab"
+)
+
+// listVariables returns all CI/CD variables of project pid, fetching all
+// pages.
+func listVariables(c gitlab.GitLaber, pid int) ([]*glab.ProjectVariable, error) {
+	all := make([]*glab.ProjectVariable, 0)
+	opts := &glab.ListProjectVariablesOptions{PerPage: ResultsPerPage, Page: 1}
+	for {
+		vs, _, err := c.ListVariables(pid, opts)
+		if err != nil {
+			return nil, err
+		}
+		if len(vs) == 0 {
+			break
+		}
+		all = append(all, vs...)
+		opts.Page++
+	}
+	return all, nil
+}
+
+// variableName returns the name of v, with its environment scope. Values
+// are never part of it, so that they don't show up in the output.
+func variableName(v *glab.ProjectVariable) string {
+	if v.EnvironmentScope == "" || v.EnvironmentScope == "*" {
+		return v.Key
+	}
+	return fmt.Sprintf("%s (%s)", v.Key, v.EnvironmentScope)
+}
+
+// migrateVariables copies the CI/CD variables of the source project, with
+// their type, protection flags and environment scope. Variables already
+// existing on target (by key and scope) are skipped.
+func (m *Migration) migrateVariables() error {
+	fmt.Println("Copying CI/CD variables ...")
+	vars, err := listVariables(m.Endpoint.SrcClient, m.srcProject.ID)
+	if err != nil {
+		return fmt.Errorf("source: can't fetch CI/CD variables: %s", err.Error())
+	}
+	fmt.Printf("Found %d CI/CD variables\n", len(vars))
+	tvs, err := listVariables(m.Endpoint.DstClient, m.dstProject.ID)
+	if err != nil {
+		return fmt.Errorf("target: can't fetch CI/CD variables: %s", err.Error())
+	}
+	existing := make(map[string]bool)
+	for _, v := range tvs {
+		existing[variableName(v)] = true
+	}
+
+	for _, v := range vars {
+		name := variableName(v)
+		if existing[name] {
+			fmt.Printf("target: CI/CD variable %s already exists, skipping...\n", name)
+			continue
+		}
+		v := v
+		vopts := &glab.CreateProjectVariableOptions{
+			Key:              &v.Key,
+			Value:            &v.Value,
+			VariableType:     &v.VariableType,
+			Protected:        &v.Protected,
+			Masked:           &v.Masked,
+			Raw:              &v.Raw,
+			EnvironmentScope: &v.EnvironmentScope,
+		}
+		if _, _, err := m.Endpoint.DstClient.CreateVariable(m.dstProject.ID, vopts); err != nil {
+			return fmt.Errorf("target: error creating CI/CD variable %s: %s", name, err.Error())
+		}
+		fmt.Printf("target: created CI/CD variable %s\n", name)
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package migration
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/gotsunami/gitlab-copy/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	glab "github.com/xanzy/go-gitlab"
+)
+
+func TestMigrateVariables(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	runs := []struct {
+		name    string                     // Sub-test name
+		config  string                     // YAML config
+		setup   func(src, dst *fakeClient) // Defines any option before calling Migrate()
+		asserts func(err error, src, dst *fakeClient)
+	}{
+		{
+			"Listing source variables fails",
+			cfg12,
+			func(src, dst *fakeClient) {
+				src.errors.listVariables = errors.New("err")
+			},
+			func(err error, src, dst *fakeClient) {
+				assert.Error(err)
+			},
+		},
+		{
+			"Listing target variables fails",
+			cfg12,
+			func(src, dst *fakeClient) {
+				dst.errors.listVariables = errors.New("err")
+			},
+			func(err error, src, dst *fakeClient) {
+				assert.Error(err)
+			},
+		},
+		{
+			"Creating variable fails",
+			cfg12,
+			func(src, dst *fakeClient) {
+				src.variables = []*glab.ProjectVariable{{Key: "TOKEN", Value: "secret", EnvironmentScope: "*"}}
+				dst.errors.createVariable = errors.New("err")
+			},
+			func(err error, src, dst *fakeClient) {
+				assert.Error(err)
+			},
+		},
+		{
+			"Variables copied with their flags and scope, existing ones skipped",
+			cfg12,
+			func(src, dst *fakeClient) {
+				src.variables = []*glab.ProjectVariable{
+					{Key: "TOKEN", Value: "secret", EnvironmentScope: "*", Protected: true, Masked: true},
+					{Key: "TOKEN", Value: "prod secret", EnvironmentScope: "production", Protected: true},
+					{Key: "CONFIG", Value: "a: b", VariableType: glab.FileVariableType, EnvironmentScope: "*"},
+				}
+				dst.variables = []*glab.ProjectVariable{{Key: "TOKEN", Value: "kept", EnvironmentScope: "*"}}
+			},
+			func(err error, src, dst *fakeClient) {
+				require.NoError(err)
+				if assert.Len(dst.variables, 3) {
+					assert.Equal("kept", dst.variables[0].Value)
+					v := dst.variables[1]
+					assert.Equal("TOKEN", v.Key)
+					assert.Equal("prod secret", v.Value)
+					assert.Equal("production", v.EnvironmentScope)
+					assert.True(v.Protected)
+					assert.False(v.Masked)
+					v = dst.variables[2]
+					assert.Equal("CONFIG", v.Key)
+					assert.Equal(glab.FileVariableType, v.VariableType)
+				}
+			},
+		},
+	}
+	for _, run := range runs {
+		t.Run(run.name, func(t *testing.T) {
+			conf, err := config.Parse(strings.NewReader(run.config))
+			require.NoError(err)
+			// Load the conf.
+			m, err := New(conf)
+			require.NoError(err)
+			_, err = m.SourceProject(m.params.SrcPrj.Name)
+			require.NoError(err)
+			_, err = m.DestProject(m.params.DstPrj.Name)
+			require.NoError(err)
+			// Setup.
+			run.setup(source(m), dest(m))
+			// Run the migration.
+			err = m.Migrate()
+			// Asserts and tear down.
+			run.asserts(err, source(m), dest(m))
+		})
+	}
+}
+
+func TestVariableName(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("TOKEN", variableName(&glab.ProjectVariable{Key: "TOKEN", Value: "secret", EnvironmentScope: "*"}))
+	assert.Equal("TOKEN", variableName(&glab.ProjectVariable{Key: "TOKEN", Value: "secret"}))
+	assert.Equal("TOKEN (review/*)", variableName(&glab.ProjectVariable{Key: "TOKEN", Value: "secret", EnvironmentScope: "review/*"}))
+}
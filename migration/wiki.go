@@ -0,0 +1,114 @@
+package migration
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+
+	glab "github.com/xanzy/go-gitlab"
+)
+
+// wikiUploadRE matches the relative links to wiki attachments in a wiki page,
+// like uploads/66dbcd21ec5d24ed6ea225176098d52b/shot.png.
+var wikiUploadRE = regexp.MustCompile(`(^|[\s("'\[])(uploads/[0-9a-f]{32}/[^\s)"'\]]+)`)
+
+// rewriteWikiUploads copies the source wiki attachments referenced in text to
+// the target wiki and returns text with the links pointing to the new
+// attachments. Links to attachments that can't be copied are left untouched.
+func (m *Migration) rewriteWikiUploads(text string) string {
+	return wikiUploadRE.ReplaceAllStringFunc(text, func(match string) string {
+		sub := wikiUploadRE.FindStringSubmatch(match)
+		prefix, upload := sub[1], sub[2]
+		nu, err := m.copyWikiUpload(upload)
+		if err != nil {
+			fmt.Printf("warning: can't copy wiki attachment %s: %s\n", upload, err.Error())
+			return match
+		}
+		return prefix + nu
+	})
+}
+
+// copyWikiUpload copies a single source wiki attachment to the target wiki
+// and returns its new relative URL. Attachments are copied once per
+// migration.
+func (m *Migration) copyWikiUpload(upload string) (string, error) {
+	if nu, ok := m.uploads[upload]; ok {
+		return nu, nil
+	}
+	src := strings.TrimSuffix(m.srcProject.WebURL, "/") + "/-/wikis/" + upload
+	data, _, err := m.Endpoint.SrcClient.DownloadFile(src)
+	if err != nil {
+		return "", fmt.Errorf("source: error downloading %s: %s", src, err.Error())
+	}
+	name := path.Base(upload)
+	if n, err := url.PathUnescape(name); err == nil {
+		name = n
+	}
+	a, _, err := m.Endpoint.DstClient.UploadWikiAttachment(m.dstProject.ID, bytes.NewReader(data), name)
+	if err != nil {
+		return "", fmt.Errorf("target: error uploading wiki attachment %s: %s", name, err.Error())
+	}
+	m.uploads[upload] = a.Link.URL
+	return a.Link.URL, nil
+}
+
+// wikiPageTitle returns the title to create page with on target, so that it
+// lands in the same directory as on source.
+func wikiPageTitle(page *glab.Wiki) string {
+	if dir := path.Dir(page.Slug); dir != "." {
+		return dir + "/" + page.Title
+	}
+	return page.Title
+}
+
+// migrateWiki copies the source wiki pages to the target project, along with
+// their attachments. Pages whose slug already exists on target are skipped.
+func (m *Migration) migrateWiki() error {
+	source := m.Endpoint.SrcClient
+	target := m.Endpoint.DstClient
+
+	srcProjectID := m.srcProject.ID
+	tarProjectID := m.dstProject.ID
+
+	fmt.Println("Copying wiki pages ...")
+	withContent := true
+	pages, _, err := source.ListWikis(srcProjectID, &glab.ListWikisOptions{WithContent: &withContent})
+	if err != nil {
+		return fmt.Errorf("source: can't fetch wiki pages: %s", err.Error())
+	}
+	fmt.Printf("Found %d wiki pages\n", len(pages))
+
+	existing, _, err := target.ListWikis(tarProjectID, nil)
+	if err != nil {
+		return fmt.Errorf("target: can't fetch wiki pages: %s", err.Error())
+	}
+	slugs := make(map[string]bool)
+	for _, p := range existing {
+		slugs[p.Slug] = true
+	}
+
+	for _, p := range pages {
+		if slugs[p.Slug] {
+			fmt.Printf("target: wiki page %s already exists, skipping...\n", p.Slug)
+			continue
+		}
+		title := wikiPageTitle(p)
+		content := m.rewriteUploads(m.rewriteWikiUploads(p.Content))
+		format := p.Format
+		opts := &glab.CreateWikiPageOptions{
+			Title:   &title,
+			Content: &content,
+			Format:  &format,
+		}
+		np, _, err := target.CreateWikiPage(tarProjectID, opts)
+		if err != nil {
+			return fmt.Errorf("target: error creating wiki page '%s': %s", p.Slug, err.Error())
+		}
+		slugs[np.Slug] = true
+		fmt.Printf("target: created wiki page %s\n", np.Slug)
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+package migration
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/gotsunami/gitlab-copy/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	glab "github.com/xanzy/go-gitlab"
+)
+
+const wikiUpload = "uploads/66dbcd21ec5d24ed6ea225176098d52b/shot.png"
+
+func TestMigrateWiki(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	runs := []struct {
+		name    string                     // Sub-test name
+		config  string                     // YAML config
+		setup   func(src, dst *fakeClient) // Defines any option before calling Migrate()
+		asserts func(err error, src, dst *fakeClient)
+	}{
+		{
+			"Listing source pages fails",
+			cfg6,
+			func(src, dst *fakeClient) {
+				src.errors.listWikis = errors.New("err")
+			},
+			func(err error, src, dst *fakeClient) {
+				assert.Error(err)
+			},
+		},
+		{
+			"Listing target pages fails",
+			cfg6,
+			func(src, dst *fakeClient) {
+				src.wikis = makeWikis("home")
+				dst.errors.listWikis = errors.New("err")
+			},
+			func(err error, src, dst *fakeClient) {
+				assert.Error(err)
+			},
+		},
+		{
+			"Creating page fails",
+			cfg6,
+			func(src, dst *fakeClient) {
+				src.wikis = makeWikis("home")
+				dst.errors.createWikiPage = errors.New("err")
+			},
+			func(err error, src, dst *fakeClient) {
+				assert.Error(err)
+			},
+		},
+		{
+			"Pages copied with their format, existing slugs skipped",
+			cfg6,
+			func(src, dst *fakeClient) {
+				src.wikis = makeWikis("home", "setup", "docs/install")
+				src.wikis[1].Format = "asciidoc"
+				dst.wikis = makeWikis("home")
+				dst.wikis[0].Content = "target home"
+			},
+			func(err error, src, dst *fakeClient) {
+				require.NoError(err)
+				if assert.Len(dst.wikis, 3) {
+					assert.Equal("target home", dst.wikis[0].Content)
+					assert.Equal("setup", dst.wikis[1].Slug)
+					assert.Equal("content of setup", dst.wikis[1].Content)
+					assert.Equal(glab.WikiFormatValue("asciidoc"), dst.wikis[1].Format)
+					assert.Equal("docs/install", dst.wikis[2].Slug)
+					assert.Equal("install", dst.wikis[2].Title)
+				}
+			},
+		},
+		{
+			"Wiki attachments copied",
+			cfg6,
+			func(src, dst *fakeClient) {
+				src.wikis = makeWikis("home")
+				src.wikis[0].Content = "![shot](" + wikiUpload + ") and ![again](" + wikiUpload + ")"
+				src.files = map[string][]byte{
+					"/-/wikis/" + wikiUpload: []byte("png"),
+				}
+			},
+			func(err error, src, dst *fakeClient) {
+				require.NoError(err)
+				if assert.Len(dst.files, 1) {
+					assert.Equal([]byte("png"), dst.files["uploads/00000000000000000000000000000000/shot.png"])
+				}
+				if assert.Len(dst.wikis, 1) {
+					assert.Equal("![shot](uploads/00000000000000000000000000000000/shot.png) and "+
+						"![again](uploads/00000000000000000000000000000000/shot.png)", dst.wikis[0].Content)
+				}
+			},
+		},
+		{
+			"Uploading wiki attachment fails, link left untouched",
+			cfg6,
+			func(src, dst *fakeClient) {
+				src.wikis = makeWikis("home")
+				src.wikis[0].Content = "![shot](" + wikiUpload + ")"
+				src.files = map[string][]byte{
+					"/-/wikis/" + wikiUpload: []byte("png"),
+				}
+				dst.errors.uploadWikiAttachment = errors.New("err")
+			},
+			func(err error, src, dst *fakeClient) {
+				require.NoError(err)
+				if assert.Len(dst.wikis, 1) {
+					assert.Equal("![shot]("+wikiUpload+")", dst.wikis[0].Content)
+				}
+			},
+		},
+	}
+	for _, run := range runs {
+		t.Run(run.name, func(t *testing.T) {
+			conf, err := config.Parse(strings.NewReader(run.config))
+			require.NoError(err)
+			// Load the conf.
+			m, err := New(conf)
+			require.NoError(err)
+			_, err = m.SourceProject(m.params.SrcPrj.Name)
+			require.NoError(err)
+			_, err = m.DestProject(m.params.DstPrj.Name)
+			require.NoError(err)
+			// Setup.
+			run.setup(source(m), dest(m))
+			// Run the migration.
+			err = m.migrateWiki()
+			// Asserts and tear down.
+			run.asserts(err, source(m), dest(m))
+		})
+	}
+}
+
+func TestMigrateWikiPhase(t *testing.T) {
+	require := require.New(t)
+
+	conf, err := config.Parse(strings.NewReader(cfg6))
+	require.NoError(err)
+	m, err := New(conf)
+	require.NoError(err)
+	src, dst := source(m), dest(m)
+	src.wikis = makeWikis("home", "setup")
+
+	require.NoError(m.Migrate())
+	require.Len(dst.wikis, 2)
+	// Running it again doesn't duplicate pages.
+	require.NoError(m.Migrate())
+	require.Len(dst.wikis, 2)
+}
+
+func makeWikis(slugs ...string) []*glab.Wiki {
+	wikis := make([]*glab.Wiki, len(slugs))
+	for k, s := range slugs {
+		wikis[k] = &glab.Wiki{
+			Slug:    s,
+			Title:   s[strings.LastIndex(s, "/")+1:],
+			Content: "content of " + s,
+			Format:  "markdown",
+		}
+	}
+	return wikis
+}
@@ -13,6 +13,12 @@ type ProjectStats struct {
 	Project                               *glab.Project
 	NbIssues, NbClosed, NbOpened, NbNotes int
 	Milestones, Labels                    map[string]int
+	// CI/CD variable names, without their values
+	Variables map[string]int
+	// Webhooks
+	Hooks []*glab.ProjectHook
+	// Feature flag names
+	FeatureFlags map[string]int
 }
 
 func NewProject(prj *glab.Project) *ProjectStats {
@@ -20,6 +26,8 @@ func NewProject(prj *glab.Project) *ProjectStats {
 	p.Project = prj
 	p.Milestones = make(map[string]int)
 	p.Labels = make(map[string]int)
+	p.Variables = make(map[string]int)
+	p.FeatureFlags = make(map[string]int)
 	return p
 }
 
@@ -125,3 +133,75 @@ func (p *ProjectStats) ComputeIssueNotes(client gitlab.GitLaber) error {
 	}
 	return nil
 }
+
+// ComputeVariables counts the CI/CD variables per key. Their values are not
+// kept.
+func (p *ProjectStats) ComputeVariables(client gitlab.GitLaber) error {
+	if client == nil {
+		return errors.New("nil client")
+	}
+
+	action := func(c gitlab.GitLaber, lo *glab.ListOptions) (bool, error) {
+		vars, _, err := client.ListVariables(p.Project.ID, (*glab.ListProjectVariablesOptions)(lo))
+		if err != nil {
+			return false, fmt.Errorf("source: can't fetch CI/CD variables: %s", err.Error())
+		}
+		if len(vars) == 0 {
+			// Exit
+			return true, nil
+		}
+		for _, v := range vars {
+			p.Variables[v.Key]++
+		}
+		return false, nil
+	}
+
+	return p.pagination(client, action)
+}
+
+// ComputeHooks fetches the webhooks of the project.
+func (p *ProjectStats) ComputeHooks(client gitlab.GitLaber) error {
+	if client == nil {
+		return errors.New("nil client")
+	}
+
+	action := func(c gitlab.GitLaber, lo *glab.ListOptions) (bool, error) {
+		hooks, _, err := client.ListProjectHooks(p.Project.ID, (*glab.ListProjectHooksOptions)(lo))
+		if err != nil {
+			return false, fmt.Errorf("source: can't fetch webhooks: %s", err.Error())
+		}
+		if len(hooks) == 0 {
+			// Exit
+			return true, nil
+		}
+		p.Hooks = append(p.Hooks, hooks...)
+		return false, nil
+	}
+
+	return p.pagination(client, action)
+}
+
+// ComputeFeatureFlags counts the feature flags of the project, per name.
+func (p *ProjectStats) ComputeFeatureFlags(client gitlab.GitLaber) error {
+	if client == nil {
+		return errors.New("nil client")
+	}
+
+	action := func(c gitlab.GitLaber, lo *glab.ListOptions) (bool, error) {
+		opts := &glab.ListProjectFeatureFlagOptions{ListOptions: glab.ListOptions{PerPage: lo.PerPage, Page: lo.Page}}
+		flags, _, err := client.ListProjectFeatureFlags(p.Project.ID, opts)
+		if err != nil {
+			return false, fmt.Errorf("source: can't fetch feature flags: %s", err.Error())
+		}
+		if len(flags) == 0 {
+			// Exit
+			return true, nil
+		}
+		for _, f := range flags {
+			p.FeatureFlags[f.Name]++
+		}
+		return false, nil
+	}
+
+	return p.pagination(client, action)
+}